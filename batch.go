@@ -0,0 +1,208 @@
+package stream
+
+import "io"
+
+// ChanBatch is a channel of blocks of values. Operations on it apply to each
+// value, but values travel between stages in blocks which avoids the cost of a
+// channel hand-off for every value. A block is owned by the stage receiving it
+// and may be modified in place.
+type ChanBatch[T any] chan []T
+
+// Batch groups the main channel values into blocks of up to n values. A block
+// is only sent once it is full or the main channel closes.
+func (c Chan[T]) Batch(n int) ChanBatch[T] {
+	s, output := spawn[ChanBatch[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		block := make([]T, 0, n)
+		for val := range each(s, c) {
+			block = append(block, val)
+			if len(block) < n {
+				continue
+			}
+			if !send(s, output, block) {
+				return
+			}
+			block = make([]T, 0, n)
+		}
+		if len(block) > 0 {
+			send(s, output, block)
+		}
+	}()
+
+	return output
+}
+
+// FromReaderBatched creates a channel that will return blocks of bytes read
+// from the io.Reader implementation. The channel will close when the reader
+// returns an error. Errors other than io.EOF are available from the channel's
+// Err method once it has closed.
+func FromReaderBatched(r io.Reader) ChanBatch[byte] {
+	s, output := spawn[ChanBatch[byte]]()
+
+	go func() {
+		defer finish(s, output)
+		for {
+			buffer := make([]byte, 4096) // Default page size.
+			n, err := r.Read(buffer)
+			if n > 0 && !send(s, output, buffer[:n]) {
+				return
+			}
+			if err != nil {
+				if err != io.EOF {
+					s.fail(err)
+				}
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Unbatch returns the values of the main channel's blocks one at a time.
+func (c ChanBatch[T]) Unbatch() Chan[T] {
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for block := range each(s, c) {
+			for _, val := range block {
+				if !send(s, output, val) {
+					return
+				}
+			}
+		}
+	}()
+
+	return output
+}
+
+// Map mutates the values of the main channel's blocks based on the passed
+// function. The passed function is called once for each value.
+func (c ChanBatch[T]) Map(f func(val T) T) ChanBatch[T] {
+	s, output := spawn[ChanBatch[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for block := range each(s, c) {
+			for i, val := range block {
+				block[i] = f(val)
+			}
+			if !send(s, output, block) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Filter filters the values of the main channel's blocks based on the passed
+// function returning true. Blocks left empty are dropped. The passed function
+// is called once for each value.
+func (c ChanBatch[T]) Filter(f func(val T) bool) ChanBatch[T] {
+	s, output := spawn[ChanBatch[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for block := range each(s, c) {
+			kept := block[:0]
+			for _, val := range block {
+				if f(val) {
+					kept = append(kept, val)
+				}
+			}
+			if len(kept) > 0 && !send(s, output, kept) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Tee passes each value of the main channel's blocks to the passed function.
+// The passed function is called once for each value.
+func (c ChanBatch[T]) Tee(f func(val T)) ChanBatch[T] {
+	s, output := spawn[ChanBatch[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for block := range each(s, c) {
+			for _, val := range block {
+				f(val)
+			}
+			if !send(s, output, block) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Slice returns a slice containing the values of the main channel's blocks
+// once the main channel closes.
+func (c ChanBatch[T]) Slice() []T {
+	output := make([]T, 0)
+
+	for block := range c {
+		output = append(output, block...)
+	}
+
+	return output
+}
+
+// WriteTo writes the values of the main channel's blocks as bytes to the
+// writer argument, in the same format as Chan.WriteTo, and returns the number
+// of bytes written. Blocks of bytes are written with a single call to the
+// writer. If the main channel ended because of an error, that error is
+// returned once the channel's values have been written.
+func (c ChanBatch[T]) WriteTo(w io.Writer) (int64, error) {
+	cw := &countingWriter{w: w}
+	for block := range c {
+		if bytes, ok := any(block).([]byte); ok {
+			if _, err := cw.Write(bytes); err != nil {
+				return cw.n, err
+			}
+			continue
+		}
+		for _, val := range block {
+			if err := write(cw, val); err != nil {
+				return cw.n, err
+			}
+		}
+	}
+	return cw.n, c.Err()
+}
+
+// countingWriter counts the bytes written to the writer it wraps.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// Err returns the error that ended the main channel, if any. It should be
+// called once the main channel has closed.
+func (c ChanBatch[T]) Err() error {
+	s := lookup(c)
+	if s == nil {
+		return nil
+	}
+	return s.error()
+}
+
+// In binds the main channel, and every stage feeding it, to the passed
+// pipeline. The main channel is returned so the call can be chained.
+func (c ChanBatch[T]) In(p *Pipeline) ChanBatch[T] {
+	p.attach(source(c))
+	return c
+}
@@ -0,0 +1,169 @@
+package stream
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"unicode"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	expected := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	result := make([][]int, 0)
+	for block := range Iota(1, 8, 1).Batch(3) {
+		result = append(result, block)
+	}
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleChan_Batch() {
+	for block := range Iota(1, 8, 1).Batch(3) {
+		fmt.Println(block)
+	}
+	// Output:
+	// [1 2 3]
+	// [4 5 6]
+	// [7]
+}
+
+func TestUnbatch(t *testing.T) {
+	expected := []int{1, 2, 3, 4, 5, 6, 7}
+	result := Iota(1, 8, 1).Batch(3).Unbatch().Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestBatchMap(t *testing.T) {
+	expected := []int{2, 4, 6, 8, 10}
+	result := Iota(1, 6, 1).Batch(2).Map(func(val int) int { return val * 2 }).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestBatchFilter(t *testing.T) {
+	expected := []int{2, 4, 6}
+	result := Iota(1, 7, 1).Batch(2).Filter(func(val int) bool { return val%2 == 0 }).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestBatchTee(t *testing.T) {
+	sum := 0
+	result := Iota(1, 6, 1).Batch(2).Tee(func(val int) { sum += val }).Slice()
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, result)
+	assert.Equal(t, 15, sum)
+}
+
+func TestFromReaderBatched(t *testing.T) {
+	expected := "LOREM IPSUM"
+	r := strings.NewReader("Lorem ipsum")
+
+	buf := new(bytes.Buffer)
+	n, err := FromReaderBatched(r).Map(func(val byte) byte {
+		return byte(unicode.ToUpper(rune(val)))
+	}).WriteTo(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(len(expected)), n)
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestFromReaderBatchedError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem"), iotest.ErrReader(failure))
+
+	buf := new(bytes.Buffer)
+	n, err := FromReaderBatched(r).WriteTo(buf)
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, int64(5), n)
+	assert.Equal(t, "Lorem", buf.String())
+}
+
+func ExampleFromReaderBatched() {
+	r := strings.NewReader("Lorem ipsum dolor sit amet")
+
+	FromReaderBatched(r).Filter(func(val byte) bool {
+		return val != ' '
+	}).WriteTo(os.Stdout)
+	// Output: Loremipsumdolorsitamet
+}
+
+func TestBatchWriteTo(t *testing.T) {
+	expected := []byte{1, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}
+
+	buf := new(bytes.Buffer)
+	n, err := Iota(1, 3, 1).Batch(8).WriteTo(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(16), n)
+	assert.Equal(t, expected, buf.Bytes())
+}
+
+func TestBatchPipeline(t *testing.T) {
+	p := NewPipeline()
+	c := Repeat(1).Batch(4).In(p).Unbatch().Take(5)
+
+	assert.Equal(t, []int{1, 1, 1, 1, 1}, c.Slice())
+	p.Stop()
+}
+
+var benchmarkData = bytes.Repeat([]byte("Lorem ipsum dolor sit amet "), 1<<14)
+
+func BenchmarkCopy(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+	for b.Loop() {
+		io.Copy(io.Discard, bytes.NewReader(benchmarkData))
+	}
+}
+
+func BenchmarkFromReaderWriteTo(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+	for b.Loop() {
+		FromReader(bytes.NewReader(benchmarkData)).WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkFromReaderBufferWriteTo(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+	for b.Loop() {
+		FromReader(bytes.NewReader(benchmarkData)).Buffer(4096).WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkFromReaderBatchedWriteTo(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+	for b.Loop() {
+		FromReaderBatched(bytes.NewReader(benchmarkData)).WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkFromReaderMapWriteTo(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+	for b.Loop() {
+		FromReader(bytes.NewReader(benchmarkData)).Map(upper).WriteTo(io.Discard)
+	}
+}
+
+func BenchmarkFromReaderBatchedMapWriteTo(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkData)))
+	for b.Loop() {
+		FromReaderBatched(bytes.NewReader(benchmarkData)).Map(upper).WriteTo(io.Discard)
+	}
+}
+
+func upper(val byte) byte {
+	if val >= 'a' && val <= 'z' {
+		return val - 32
+	}
+	return val
+}
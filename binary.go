@@ -0,0 +1,159 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+)
+
+// ReadBinary creates a channel that will return values of the fixed size type
+// T decoded from the io.Reader implementation using the passed byte order. It
+// reads the format written by WriteTo, so int and uint values are read as 64
+// bit integers. The channel will close when the reader is exhausted. A value
+// cut short, or a type of variable size, is reported by the channel's Err
+// method once it has closed. Use ReadFramed for strings and byte slices.
+func ReadBinary[T any](r io.Reader, order binary.ByteOrder) Chan[T] {
+	s, output := spawn[Chan[T]]()
+	reader := bufio.NewReader(r)
+
+	go func() {
+		defer finish(s, output)
+		var zero T
+		if _, ok := any(zero).(int); !ok {
+			if _, ok := any(zero).(uint); !ok && binary.Size(zero) < 0 {
+				s.fail(fmt.Errorf("stream: can't read %T as binary, it has no fixed size", zero))
+				return
+			}
+		}
+		for {
+			val, err := readBinary[T](reader, order)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				s.fail(err)
+				return
+			}
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// readBinary reads a single value of a fixed size type.
+func readBinary[T any](r io.Reader, order binary.ByteOrder) (T, error) {
+	var val T
+	var err error
+	switch v := any(&val).(type) {
+	case *int:
+		var n int64
+		err = binary.Read(r, order, &n)
+		*v = int(n)
+	case *uint:
+		var n uint64
+		err = binary.Read(r, order, &n)
+		*v = uint(n)
+	default:
+		err = binary.Read(r, order, v)
+	}
+	return val, err
+}
+
+// ReadFramed creates a channel that will return the strings or byte slices
+// read from the io.Reader implementation, each preceded by its length as a 32
+// bit integer in the passed byte order, as written by WriteFramed. The channel
+// will close when the reader is exhausted. A value cut short is reported by
+// the channel's Err method once it has closed.
+func ReadFramed[T ~string | ~[]byte](r io.Reader, order binary.ByteOrder) Chan[T] {
+	s, output := spawn[Chan[T]]()
+	reader := bufio.NewReader(r)
+
+	go func() {
+		defer finish(s, output)
+		for {
+			frame, err := readFrame(reader, order)
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				s.fail(err)
+				return
+			}
+			if !send(s, output, T(frame)) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// readFrame reads a single length-prefixed frame. The length isn't trusted to
+// size the frame up front, the frame grows as its bytes are read so a corrupt
+// length can't allocate more than the input holds.
+func readFrame(r io.Reader, order binary.ByteOrder) ([]byte, error) {
+	var n uint32
+	if err := binary.Read(r, order, &n); err != nil {
+		return nil, err
+	}
+	frame := bytes.NewBuffer(make([]byte, 0, min(n, bytes.MinRead)))
+	if _, err := io.CopyN(frame, r, int64(n)); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return frame.Bytes(), nil
+}
+
+// WriteFramed writes the main channel's strings or byte slices to the writer
+// argument, each preceded by its length as a 32 bit integer in the passed byte
+// order, to be read by ReadFramed. Values of other types are an error. If the
+// main channel ended because of an error, that error is returned once the
+// channel's values have been written.
+func (c Chan[T]) WriteFramed(w io.Writer, order binary.ByteOrder) error {
+	buffer := bufio.NewWriter(w)
+	for val := range c {
+		var frame []byte
+		var err error
+		switch v := reflect.ValueOf(val); {
+		case v.Kind() == reflect.String:
+			frame = []byte(v.String())
+		case v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8:
+			frame = v.Bytes()
+		default:
+			err = fmt.Errorf("stream: can't write %T as a frame", val)
+		}
+		if err == nil {
+			err = writeFrame(buffer, order, frame)
+		}
+		if err != nil {
+			// The frames before the value are still written.
+			buffer.Flush()
+			return err
+		}
+	}
+	if err := buffer.Flush(); err != nil {
+		return err
+	}
+	return c.Err()
+}
+
+// writeFrame writes a single length-prefixed frame.
+func writeFrame(w io.Writer, order binary.ByteOrder, frame []byte) error {
+	if uint64(len(frame)) > math.MaxUint32 {
+		return fmt.Errorf("stream: frame of %d bytes is too long", len(frame))
+	}
+	if err := binary.Write(w, order, uint32(len(frame))); err != nil {
+		return err
+	}
+	_, err := w.Write(frame)
+	return err
+}
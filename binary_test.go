@@ -0,0 +1,136 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sample struct {
+	ID    uint16
+	Value float32
+	Flags [2]bool
+}
+
+func TestReadBinaryInt(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, FromSlice([]int{1, -2, 3}).WriteTo(buf))
+
+	c := ReadBinary[int](buf, binary.LittleEndian)
+	assert.Equal(t, []int{1, -2, 3}, c.Slice())
+	assert.NoError(t, c.Err())
+}
+
+func TestReadBinaryUint(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, FromSlice([]uint{1, 2}).WriteTo(buf))
+
+	assert.Equal(t, []uint{1, 2}, ReadBinary[uint](buf, binary.LittleEndian).Slice())
+}
+
+func TestReadBinaryStruct(t *testing.T) {
+	expected := []sample{{1, 1.5, [2]bool{true, false}}, {2, -3, [2]bool{false, true}}}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, FromSlice(expected).WriteTo(buf))
+
+	c := ReadBinary[sample](buf, binary.LittleEndian)
+	assert.Equal(t, expected, c.Slice())
+	assert.NoError(t, c.Err())
+}
+
+func TestReadBinaryByteOrder(t *testing.T) {
+	r := bytes.NewReader([]byte{0, 1, 0, 2})
+
+	assert.Equal(t, []uint16{1, 2}, ReadBinary[uint16](r, binary.BigEndian).Slice())
+}
+
+func TestReadBinaryTruncated(t *testing.T) {
+	c := ReadBinary[uint32](bytes.NewReader([]byte{1, 0, 0, 0, 2, 0}), binary.LittleEndian)
+
+	assert.Equal(t, []uint32{1}, c.Slice())
+	assert.ErrorIs(t, c.Err(), io.ErrUnexpectedEOF)
+}
+
+func TestReadBinaryVariableSize(t *testing.T) {
+	c := ReadBinary[string](strings.NewReader("Lorem"), binary.LittleEndian)
+
+	assert.Equal(t, []string{}, c.Slice())
+	assert.Error(t, c.Err())
+}
+
+func ExampleReadBinary() {
+	buf := new(bytes.Buffer)
+	Iota(1, 4, 1).WriteTo(buf)
+
+	result := ReadBinary[int](buf, binary.LittleEndian).Slice()
+
+	fmt.Println(result)
+	// Output: [1 2 3]
+}
+
+func TestFramed(t *testing.T) {
+	expected := []string{"ab", "c", "", "Lorem ipsum"}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, FromSlice(expected).WriteFramed(buf, binary.BigEndian))
+	assert.Equal(t, []byte{0, 0, 0, 2, 'a', 'b', 0, 0, 0, 1, 'c'}, buf.Bytes()[:11])
+
+	c := ReadFramed[string](buf, binary.BigEndian)
+	assert.Equal(t, expected, c.Slice())
+	assert.NoError(t, c.Err())
+}
+
+func TestFramedBytes(t *testing.T) {
+	expected := [][]byte{{1, 2}, {3}}
+
+	buf := new(bytes.Buffer)
+	assert.NoError(t, FromSlice(expected).WriteFramed(buf, binary.LittleEndian))
+
+	assert.Equal(t, expected, ReadFramed[[]byte](buf, binary.LittleEndian).Slice())
+}
+
+func TestReadFramedTruncated(t *testing.T) {
+	c := ReadFramed[string](bytes.NewReader([]byte{1, 0, 0, 0, 'a', 5, 0, 0, 0, 'b'}), binary.LittleEndian)
+
+	assert.Equal(t, []string{"a"}, c.Slice())
+	assert.ErrorIs(t, c.Err(), io.ErrUnexpectedEOF)
+}
+
+func TestReadFramedCorruptLength(t *testing.T) {
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	_, err := readFrame(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 'a'}), binary.LittleEndian)
+	runtime.ReadMemStats(&after)
+
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	assert.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(1<<20))
+}
+
+func TestWriteFramedType(t *testing.T) {
+	err := Iota(1, 3, 1).WriteFramed(new(bytes.Buffer), binary.LittleEndian)
+
+	assert.Error(t, err)
+
+	buf := new(bytes.Buffer)
+	err = FromSlice([]any{"ab", 1, "c"}).WriteFramed(buf, binary.LittleEndian)
+
+	assert.Error(t, err)
+	assert.Equal(t, []byte{2, 0, 0, 0, 'a', 'b'}, buf.Bytes())
+}
+
+func ExampleChan_WriteFramed() {
+	buf := new(bytes.Buffer)
+	FromSlice([]string{"ab", "c"}).WriteFramed(buf, binary.LittleEndian)
+
+	result := ReadFramed[string](buf, binary.LittleEndian).Slice()
+
+	fmt.Printf("%q\n", result)
+	// Output: ["ab" "c"]
+}
@@ -0,0 +1,259 @@
+package stream
+
+import "errors"
+
+// ErrDisconnected is reported by the Err method of a channel returned by
+// BroadcastWith when it was disconnected for falling behind.
+var ErrDisconnected = errors.New("stream: disconnected for falling behind")
+
+// Overflow decides what happens to a value broadcast to a subscriber whose
+// buffer is full.
+type Overflow int
+
+const (
+	// Block waits for the subscriber to make room, holding back every other
+	// subscriber until it does.
+	Block Overflow = iota
+
+	// DropOldest removes the oldest value from the subscriber's buffer to
+	// make room for the value.
+	DropOldest
+
+	// DropNewest drops the value for that subscriber.
+	DropNewest
+
+	// Disconnect closes the subscriber's channel once the values in its
+	// buffer have been read. Its Err method then reports ErrDisconnected.
+	Disconnect
+)
+
+// Broadcasting configures how BroadcastWith passes values to its subscribers.
+type Broadcasting struct {
+	// Buffer is the number of values held for each subscriber that has not
+	// yet read them. It is at least 1 unless Overflow is Block, as values
+	// could otherwise only be passed to subscribers already waiting for one.
+	Buffer int
+
+	// Overflow decides what happens to a value for a subscriber whose buffer
+	// is full.
+	Overflow Overflow
+}
+
+// subscriber is a channel returned by a broadcasting stage.
+type subscriber[T any] struct {
+	stage  *stage
+	feed   chan T
+	closed bool
+}
+
+// close closes the subscriber's feed, once.
+func (sub *subscriber[T]) close() {
+	if !sub.closed {
+		sub.closed = true
+		close(sub.feed)
+	}
+}
+
+// Broadcast returns n channels that each return every main channel value. A
+// value is only read from the main channel once every channel has taken the
+// one before it, so every channel must be read.
+func (c Chan[T]) Broadcast(n int) []Chan[T] {
+	return c.BroadcastWith(n, Broadcasting{})
+}
+
+// BroadcastWith returns n channels that each return every main channel value,
+// using the passed configuration to decide what happens to values for channels
+// that fall behind. Channels that are disconnected, or no longer read within a
+// pipeline, are skipped. The main channel is no longer read once every channel
+// has gone.
+func (c Chan[T]) BroadcastWith(n int, config Broadcasting) []Chan[T] {
+	s := newStage(c)
+	s.join()
+
+	if config.Overflow != Block {
+		config.Buffer = max(config.Buffer, 1)
+	}
+
+	outputs := make([]Chan[T], n)
+	subs := make([]*subscriber[T], n)
+	for i := range n {
+		feed := make(chan T, config.Buffer)
+		sub, output := subscribe(s, feed)
+		outputs[i], subs[i] = output, &subscriber[T]{stage: sub, feed: feed}
+	}
+
+	go func() {
+		defer func() {
+			s.settle()
+			for _, sub := range subs {
+				sub.close()
+			}
+			s.exit()
+		}()
+		live := n
+		for val := range each(s, c) {
+			for _, sub := range subs {
+				if sub.closed || broadcast(s, sub, val, config.Overflow) {
+					continue
+				}
+				select {
+				case <-s.stop:
+					return
+				default:
+				}
+				sub.close()
+				live--
+			}
+			if live == 0 {
+				return
+			}
+		}
+	}()
+
+	return outputs
+}
+
+// subscribe starts the stage passing values sent on the passed feed by the
+// passed fan-out stage to a channel of its own.
+func subscribe[T any](b *stage, feed chan T) (*stage, Chan[T]) {
+	s := newStage()
+	s.follow(b)
+	output := makeOutput[Chan[T]](s, s.capacity())
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, feed) {
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return s, output
+}
+
+// broadcast passes a value to a subscriber according to the passed overflow
+// policy. It returns false if the subscriber has gone, or the broadcasting
+// stage was stopped.
+func broadcast[T any](s *stage, sub *subscriber[T], val T, overflow Overflow) bool {
+	select {
+	case <-sub.stage.exited:
+		return false
+	default:
+	}
+	select {
+	case sub.feed <- val:
+		return true
+	default:
+	}
+
+	switch overflow {
+	case DropOldest:
+		for {
+			select {
+			case sub.feed <- val:
+				return true
+			default:
+			}
+			select {
+			case <-sub.feed:
+			default:
+			}
+		}
+	case DropNewest:
+		return true
+	case Disconnect:
+		sub.stage.fail(ErrDisconnected)
+		return false
+	}
+
+	select {
+	case sub.feed <- val:
+		return true
+	case <-sub.stage.exited:
+		return false
+	case <-s.stop:
+		return false
+	}
+}
+
+// Distribute returns n channels that take turns returning main channel values,
+// in order. A value waits for its channel to be read even if others are ready,
+// use Balance to pass values on to whichever channel is read first. Channels
+// no longer read within a pipeline are skipped.
+func (c Chan[T]) Distribute(n int) []Chan[T] {
+	s := newStage(c)
+	s.join()
+
+	outputs := make([]Chan[T], n)
+	subs := make([]*subscriber[T], n)
+	for i := range n {
+		feed := make(chan T)
+		sub, output := subscribe(s, feed)
+		outputs[i], subs[i] = output, &subscriber[T]{stage: sub, feed: feed}
+	}
+
+	go func() {
+		defer func() {
+			s.settle()
+			for _, sub := range subs {
+				sub.close()
+			}
+			s.exit()
+		}()
+		live, next := n, 0
+		for val := range each(s, c) {
+			for live > 0 {
+				sub := subs[next%n]
+				next++
+				if sub.closed {
+					continue
+				}
+				if broadcast(s, sub, val, Block) {
+					break
+				}
+				select {
+				case <-s.stop:
+					return
+				default:
+				}
+				sub.close()
+				live--
+			}
+			if live == 0 {
+				return
+			}
+		}
+	}()
+
+	return outputs
+}
+
+// Balance returns n channels that share the main channel values, each value
+// being returned by whichever channel is read first. This spreads values over
+// consumers of differing speeds, such as a pool of workers.
+func (c Chan[T]) Balance(n int) []Chan[T] {
+	s := newStage(c)
+	s.join()
+
+	outputs := make([]Chan[T], n)
+	feed := make(chan T)
+	for i := range n {
+		_, outputs[i] = subscribe(s, feed)
+	}
+
+	go func() {
+		defer func() {
+			s.settle()
+			close(feed)
+			s.exit()
+		}()
+		for val := range each(s, c) {
+			if !send(s, feed, val) {
+				return
+			}
+		}
+	}()
+
+	return outputs
+}
@@ -0,0 +1,154 @@
+package stream
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readAll reads every passed channel concurrently.
+func readAll[T any](chans []Chan[T]) [][]T {
+	var wg sync.WaitGroup
+	result := make([][]T, len(chans))
+	for i, c := range chans {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result[i] = c.Slice()
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+func TestBroadcast(t *testing.T) {
+	expected := []int{1, 2, 3, 4, 5}
+	result := readAll(Iota(1, 6, 1).Broadcast(3))
+
+	assert.Equal(t, [][]int{expected, expected, expected}, result)
+}
+
+func ExampleChan_Broadcast() {
+	subs := Iota(1, 4, 1).Broadcast(2)
+	squares := subs[1].Map(func(val int) int { return val * val })
+
+	result := ZipWith(subs[0], squares, func(a, b int) string {
+		return fmt.Sprintf("%d:%d", a, b)
+	}).Slice()
+
+	fmt.Println(result)
+	// Output: [1:1 2:4 3:9]
+}
+
+// feedFast sends values to a broadcast, reading each from the passed channel
+// before sending the next so that only other channels can fall behind.
+func feedFast(t *testing.T, input chan int, fast Chan[int]) {
+	t.Helper()
+	for i := 1; i <= 10; i++ {
+		input <- i
+		assert.Equal(t, i, <-fast)
+	}
+	close(input)
+	assert.Equal(t, []int{}, fast.Slice())
+}
+
+func TestBroadcastDropNewest(t *testing.T) {
+	input := make(chan int)
+	subs := Chan[int](input).BroadcastWith(2, Broadcasting{Buffer: 2, Overflow: DropNewest})
+	feedFast(t, input, subs[0])
+	slow := subs[1].Slice()
+	assert.LessOrEqual(t, len(slow), 3)
+	assert.Equal(t, []int{1, 2}, slow[:2])
+}
+
+func TestBroadcastDropOldest(t *testing.T) {
+	input := make(chan int)
+	subs := Chan[int](input).BroadcastWith(2, Broadcasting{Buffer: 2, Overflow: DropOldest})
+	feedFast(t, input, subs[0])
+	slow := subs[1].Slice()
+	assert.LessOrEqual(t, len(slow), 3)
+	assert.Equal(t, []int{9, 10}, slow[len(slow)-2:])
+}
+
+func TestBroadcastDropOldestUnbuffered(t *testing.T) {
+	input := make(chan int)
+	subs := Chan[int](input).BroadcastWith(2, Broadcasting{Overflow: DropOldest})
+	feedFast(t, input, subs[0])
+	slow := subs[1].Slice()
+	assert.LessOrEqual(t, len(slow), 2)
+	assert.Equal(t, 10, slow[len(slow)-1])
+}
+
+func TestBroadcastDisconnect(t *testing.T) {
+	input := make(chan int)
+	subs := Chan[int](input).BroadcastWith(2, Broadcasting{Buffer: 2, Overflow: Disconnect})
+	feedFast(t, input, subs[0])
+	assert.NoError(t, subs[0].Err())
+
+	slow := subs[1].Slice()
+	assert.LessOrEqual(t, len(slow), 3)
+	assert.Equal(t, []int{1, 2}, slow[:2])
+	assert.ErrorIs(t, subs[1].Err(), ErrDisconnected)
+}
+
+func TestBroadcastUnwinds(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	subs := Repeat(1).In(p).Broadcast(2)
+	result := readAll([]Chan[int]{subs[0].Take(3), subs[1].Take(5)})
+
+	assert.Equal(t, [][]int{{1, 1, 1}, {1, 1, 1, 1, 1}}, result)
+	p.Wait()
+	assert.NoError(t, p.Err())
+}
+
+func TestDistribute(t *testing.T) {
+	expected := [][]int{{1, 4}, {2, 5}, {3, 6}}
+	result := readAll(Iota(1, 7, 1).Distribute(3))
+
+	assert.Equal(t, expected, result)
+}
+
+func TestDistributeUnwinds(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	outputs := Iota(1, 1000, 1).In(p).Distribute(2)
+	result := readAll([]Chan[int]{outputs[0].Take(2), outputs[1]})
+
+	assert.Equal(t, []int{1, 3}, result[0])
+	assert.Equal(t, []int{2, 4}, result[1][:2])
+	assert.Equal(t, 999, result[1][len(result[1])-1])
+}
+
+func TestBalance(t *testing.T) {
+	result := slices.Concat(readAll(Iota(1, 101, 1).Balance(4))...)
+
+	assert.ElementsMatch(t, Iota(1, 101, 1).Slice(), result)
+}
+
+func TestBalanceSlowConsumer(t *testing.T) {
+	outputs := Iota(1, 11, 1).Balance(2)
+
+	fast := outputs[0].Slice()
+	slow := outputs[1].Slice()
+
+	assert.LessOrEqual(t, len(slow), 1)
+	assert.ElementsMatch(t, Iota(1, 11, 1).Slice(), append(fast, slow...))
+}
+
+func ExampleChan_Balance() {
+	square := func(val int) int {
+		return val * val
+	}
+
+	workers := Iota(1, 101, 1).Balance(3)
+	results := workers[0].Map(square).Merge(workers[1].Map(square), workers[2].Map(square))
+
+	fmt.Println(Sum(results))
+	// Output: 338350
+}
@@ -0,0 +1,93 @@
+package stream
+
+import "time"
+
+// Clock provides the time to time-dependent generators and operators. A clock
+// is set for a pipeline using WithClock and is used by stages created from its
+// channels, and by generators created using the pipeline's methods. Stages
+// outside of a pipeline use the system clock. The streamtest package provides
+// a fake clock for testing.
+type Clock interface {
+	Now() time.Time
+	NewTimer(d time.Duration) Timer
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer sends the current time on its channel once its duration has passed.
+// It behaves like time.Timer.
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// Ticker sends the current time on its channel every time its duration has
+// passed. It behaves like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+	Reset(d time.Duration)
+}
+
+// SystemClock is the clock backed by the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) NewTimer(d time.Duration) Timer {
+	return systemTimer{time.NewTimer(d)}
+}
+
+func (systemClock) NewTicker(d time.Duration) Ticker {
+	return systemTicker{time.NewTicker(d)}
+}
+
+type systemTimer struct {
+	*time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time {
+	return t.Timer.C
+}
+
+type systemTicker struct {
+	*time.Ticker
+}
+
+func (t systemTicker) C() <-chan time.Time {
+	return t.Ticker.C
+}
+
+// WithClock sets the clock used by the time-dependent stages of the pipeline.
+func WithClock(clock Clock) Option {
+	return func(p *Pipeline) {
+		p.clock = clock
+	}
+}
+
+// clock returns the clock of the pipeline the stage will join.
+func (s *stage) clock() Clock {
+	for _, u := range s.upstream {
+		if p := u.bound(); p != nil {
+			return p.clock
+		}
+	}
+	return SystemClock
+}
+
+// sleep waits for the passed duration. It returns false if the stage was
+// stopped before the duration passed.
+func sleep(s *stage, clock Clock, d time.Duration) bool {
+	timer := clock.NewTimer(d)
+	select {
+	case <-timer.C():
+		return true
+	case <-s.stop:
+		timer.Stop()
+		return false
+	}
+}
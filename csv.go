@@ -0,0 +1,335 @@
+package stream
+
+import (
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FromCSV creates a channel that will return the records read from the CSV
+// data of the io.Reader implementation. The channel will close when the reader
+// is exhausted. Errors reading the records are available from the channel's Err
+// method once it has closed.
+func FromCSV(r io.Reader) Chan[[]string] {
+	s, output := spawn[Chan[[]string]]()
+	reader := csv.NewReader(r)
+
+	go func() {
+		defer finish(s, output)
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				s.fail(err)
+				return
+			}
+			if !send(s, output, record) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// FromCSVStructs creates a channel that will return a value of the struct T
+// for each record read from the CSV data of the io.Reader implementation. The
+// first record is the header, its columns are matched to the fields of T by
+// their csv tag, or name if untagged. Columns without a field are ignored. A
+// field tagged "-" is skipped. Strings, integers, floats, bools, durations and
+// encoding.TextUnmarshaler implementations are converted from text, and times
+// use RFC 3339 unless the tag sets a layout, as in `csv:"date,2006-01-02"`.
+// Embedded struct pointers are allocated to set the fields promoted from them,
+// unless their cells are empty.
+// Records that can't be converted are handled by the passed policy, a dead
+// letter policy receiving them as a Failure[[]string].
+func FromCSVStructs[T any](r io.Reader, policy ErrPolicy) Chan[T] {
+	checkPolicy[[]string](policy)
+	s, output := spawn[Chan[T]]()
+	reader := csv.NewReader(r)
+
+	go func() {
+		defer finish(s, output)
+		fields, err := csvFields(reflect.TypeFor[T]())
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		header, err := reader.Read()
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			s.fail(err)
+			return
+		}
+		columns := make([]*csvField, len(header))
+		for i, name := range header {
+			for j := range fields {
+				if fields[j].name == name {
+					columns[i] = &fields[j]
+				}
+			}
+		}
+		for {
+			record, err := reader.Read()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				s.fail(err)
+				return
+			}
+			var val T
+			if err := decodeCSV(reflect.ValueOf(&val).Elem(), columns, record); err != nil {
+				if !handle(s, policy, record, err) {
+					return
+				}
+				continue
+			}
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// WriteCSV writes the main channel values to the writer argument as CSV
+// records. Values of type []string are written as they are. Structs are
+// written after a header naming their fields, using the same tags and
+// conversions as FromCSVStructs. Fields promoted from a nil embedded pointer
+// are written as empty cells. If the main channel ended because of an
+// error, that error is returned once the channel's values have been written.
+func (c Chan[T]) WriteCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+
+	if _, ok := any(*new(T)).([]string); ok {
+		for val := range c {
+			if err := writer.Write(any(val).([]string)); err != nil {
+				return err
+			}
+		}
+	} else {
+		fields, err := csvFields(reflect.TypeFor[T]())
+		if err != nil {
+			return err
+		}
+		header := make([]string, len(fields))
+		for i, field := range fields {
+			header[i] = field.name
+		}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		record := make([]string, len(fields))
+		for val := range c {
+			v := reflect.ValueOf(val)
+			for i, field := range fields {
+				f, err := v.FieldByIndexErr(field.index)
+				if err != nil {
+					// The field is promoted from a nil embedded pointer.
+					record[i] = ""
+					continue
+				}
+				text, err := formatCSV(f, field.layout)
+				if err != nil {
+					// The records before the value are still written.
+					writer.Flush()
+					return fmt.Errorf("stream: column %q: %w", field.name, err)
+				}
+				record[i] = text
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return c.Err()
+}
+
+// csvField maps a CSV column to a struct field. An optional field is promoted
+// from an embedded pointer, which is left nil if its cell is empty.
+type csvField struct {
+	name     string
+	index    []int
+	layout   string
+	optional bool
+}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// csvFields returns the CSV columns of the passed struct type.
+func csvFields(t reflect.Type) ([]csvField, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("stream: can't map CSV columns to %v", t)
+	}
+	var fields []csvField
+	for _, f := range reflect.VisibleFields(t) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		name, layout, _ := strings.Cut(f.Tag.Get("csv"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if layout == "" && f.Type == timeType {
+			layout = time.RFC3339
+		}
+		fields = append(fields, csvField{name: name, index: f.Index, layout: layout, optional: promoted(t, f.Index)})
+	}
+	return fields, nil
+}
+
+// promoted returns true if the field of the passed struct type with the passed
+// index is promoted from an embedded pointer.
+func promoted(t reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		t = t.Field(x).Type
+		if t.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeCSV sets the fields of the passed struct from the columns of a record.
+func decodeCSV(v reflect.Value, columns []*csvField, record []string) error {
+	for i, text := range record {
+		if i >= len(columns) || columns[i] == nil {
+			continue
+		}
+		field := columns[i]
+		if field.optional && text == "" {
+			continue
+		}
+		f, err := allocField(v, field.index)
+		if err != nil {
+			return fmt.Errorf("stream: column %q: %w", field.name, err)
+		}
+		if err := parseCSV(f, text, field.layout); err != nil {
+			return fmt.Errorf("stream: column %q: %w", field.name, err)
+		}
+	}
+	return nil
+}
+
+// allocField returns the nested field of the passed struct with the passed
+// index, allocating the embedded struct pointers it is promoted through.
+func allocField(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}, fmt.Errorf("can't set embedded pointer to unexported %v", v.Type().Elem())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// parseCSV sets the passed field from its text.
+func parseCSV(v reflect.Value, text, layout string) error {
+	switch {
+	case v.Type() == timeType:
+		t, err := time.Parse(layout, text)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	case reflect.PointerTo(v.Type()).Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		n, err := strconv.ParseUint(text, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(text, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	default:
+		return fmt.Errorf("unsupported type %v", v.Type())
+	}
+	return nil
+}
+
+// formatCSV returns the text of the passed field.
+func formatCSV(v reflect.Value, layout string) (string, error) {
+	switch {
+	case v.Type() == timeType:
+		return v.Interface().(time.Time).Format(layout), nil
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String(), nil
+	case v.Type().Implements(textMarshalerType):
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %v", v.Type())
+}
@@ -0,0 +1,245 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type reading struct {
+	Sensor   string        `csv:"sensor"`
+	Value    float64       `csv:"value"`
+	Count    int           `csv:"count"`
+	Valid    bool          `csv:"valid"`
+	Date     time.Time     `csv:"date,2006-01-02"`
+	Interval time.Duration `csv:"interval"`
+	Note     string        `csv:"-"`
+	Unit     string
+}
+
+const readings = `sensor,value,count,valid,date,interval,extra,Unit
+a,1.5,3,true,2024-01-02,1m30s,x,C
+b,oops,1,false,2024-01-03,1s,y,F
+c,-2,10,1,2024-01-04,2h,z,K
+`
+
+func TestFromCSV(t *testing.T) {
+	expected := [][]string{{"a", "b"}, {"1", "2,3"}}
+	c := FromCSV(strings.NewReader("a,b\n1,\"2,3\"\n"))
+
+	assert.Equal(t, expected, c.Slice())
+	assert.NoError(t, c.Err())
+}
+
+func TestFromCSVError(t *testing.T) {
+	c := FromCSV(strings.NewReader("a,b\n1,2,3\n"))
+
+	assert.Equal(t, [][]string{{"a", "b"}}, c.Slice())
+	assert.ErrorIs(t, c.Err(), csv.ErrFieldCount)
+}
+
+func ExampleFromCSV() {
+	r := strings.NewReader("name,age\nLorem,3\nipsum,5\n")
+
+	for record := range FromCSV(r).Drop(1) {
+		fmt.Println(record[0])
+	}
+	// Output:
+	// Lorem
+	// ipsum
+}
+
+func TestFromCSVStructs(t *testing.T) {
+	expected := []reading{
+		{
+			Sensor:   "a",
+			Value:    1.5,
+			Count:    3,
+			Valid:    true,
+			Date:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+			Interval: 90 * time.Second,
+			Unit:     "C",
+		},
+		{
+			Sensor:   "c",
+			Value:    -2,
+			Count:    10,
+			Valid:    true,
+			Date:     time.Date(2024, 1, 4, 0, 0, 0, 0, time.UTC),
+			Interval: 2 * time.Hour,
+			Unit:     "K",
+		},
+	}
+	failed := make(chan Failure[[]string], 1)
+	c := FromCSVStructs[reading](strings.NewReader(readings), DeadLetter(failed))
+
+	assert.Equal(t, expected, c.Slice())
+	assert.NoError(t, c.Err())
+
+	failure := <-failed
+	assert.Equal(t, "b", failure.Val[0])
+	assert.ErrorIs(t, failure.Err, strconv.ErrSyntax)
+	assert.ErrorContains(t, failure.Err, `column "value"`)
+}
+
+func TestFromCSVStructsStop(t *testing.T) {
+	c := FromCSVStructs[reading](strings.NewReader(readings), StopOnError)
+
+	assert.Len(t, c.Slice(), 1)
+	assert.ErrorIs(t, c.Err(), strconv.ErrSyntax)
+}
+
+func TestFromCSVStructsNotStruct(t *testing.T) {
+	c := FromCSVStructs[int](strings.NewReader(readings), StopOnError)
+
+	assert.Equal(t, []int{}, c.Slice())
+	assert.Error(t, c.Err())
+}
+
+func TestFromCSVStructsEmpty(t *testing.T) {
+	c := FromCSVStructs[reading](strings.NewReader(""), StopOnError)
+
+	assert.Equal(t, []reading{}, c.Slice())
+	assert.NoError(t, c.Err())
+}
+
+func ExampleFromCSVStructs() {
+	type person struct {
+		Name string `csv:"name"`
+		Age  int    `csv:"age"`
+	}
+	r := strings.NewReader("name,age\nLorem,3\nipsum,5\n")
+
+	for p := range FromCSVStructs[person](r, StopOnError) {
+		fmt.Println(p.Name, p.Age+1)
+	}
+	// Output:
+	// Lorem 4
+	// ipsum 6
+}
+
+func TestWriteCSV(t *testing.T) {
+	input := []reading{{
+		Sensor:   "a",
+		Value:    1.5,
+		Count:    3,
+		Valid:    true,
+		Date:     time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Interval: 90 * time.Second,
+		Note:     "ignored",
+		Unit:     "C",
+	}}
+
+	buf := new(bytes.Buffer)
+	err := FromSlice(input).WriteCSV(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "sensor,value,count,valid,date,interval,Unit\na,1.5,3,true,2024-01-02,1m30s,C\n", buf.String())
+
+	input[0].Note = ""
+	assert.Equal(t, input, FromCSVStructs[reading](buf, StopOnError).Slice())
+}
+
+type Origin struct {
+	Source string    `csv:"source"`
+	Year   int       `csv:"year"`
+	Date   time.Time `csv:"date,2006-01-02"`
+}
+
+type origin Origin
+
+type tagged struct {
+	Name string `csv:"name"`
+	*Origin
+}
+
+type hidden struct {
+	Name string `csv:"name"`
+	*origin
+}
+
+func TestCSVEmbeddedPointer(t *testing.T) {
+	buf := new(bytes.Buffer)
+	input := []tagged{
+		{Name: "a"},
+		{Name: "b", Origin: &Origin{Source: "x", Year: 2024, Date: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)}},
+	}
+	err := FromSlice(input).WriteCSV(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "name,source,year,date\na,,,\nb,x,2024,2024-01-02\n", buf.String())
+
+	c := FromCSVStructs[tagged](buf, StopOnError)
+	assert.Equal(t, input, c.Slice())
+	assert.NoError(t, c.Err())
+
+	c = FromCSVStructs[tagged](strings.NewReader("name,year\na,2024\n"), StopOnError)
+	assert.Equal(t, []tagged{{Name: "a", Origin: &Origin{Year: 2024}}}, c.Slice())
+
+	h := FromCSVStructs[hidden](strings.NewReader("name,source\na,x\n"), StopOnError)
+	assert.Equal(t, []hidden{}, h.Slice())
+	assert.ErrorContains(t, h.Err(), "unexported")
+}
+
+type level int
+
+func (l level) MarshalText() ([]byte, error) {
+	if l < 0 {
+		return nil, errors.New("negative level")
+	}
+	return []byte(strconv.Itoa(int(l))), nil
+}
+
+func TestWriteCSVFormatError(t *testing.T) {
+	type row struct {
+		Level level `csv:"level"`
+	}
+
+	buf := new(bytes.Buffer)
+	err := FromSlice([]row{{1}, {-1}, {2}}).WriteCSV(buf)
+
+	assert.ErrorContains(t, err, "negative level")
+	assert.Equal(t, "level\n1\n", buf.String())
+}
+
+func TestWriteCSVRecords(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := FromSlice([][]string{{"a", "b"}, {"1", "2,3"}}).WriteCSV(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "a,b\n1,\"2,3\"\n", buf.String())
+}
+
+func TestWriteCSVError(t *testing.T) {
+	failure := errors.New("failure")
+	c := FromCSVStructs[reading](strings.NewReader(readings), StopOnError)
+
+	err := c.MapErr(func(val reading) (reading, error) {
+		return val, failure
+	}, StopOnError).WriteCSV(new(bytes.Buffer))
+	assert.Equal(t, failure, err)
+
+	err = Iota(1, 3, 1).WriteCSV(new(bytes.Buffer))
+	assert.Error(t, err)
+}
+
+func ExampleChan_WriteCSV() {
+	type person struct {
+		Name string `csv:"name"`
+		Age  int    `csv:"age"`
+	}
+
+	FromSlice([]person{{"Lorem", 3}, {"ipsum", 5}}).WriteCSV(os.Stdout)
+	// Output:
+	// name,age
+	// Lorem,3
+	// ipsum,5
+}
@@ -0,0 +1,130 @@
+package stream
+
+import (
+	"hash/maphash"
+	"math"
+)
+
+// Distinct iterates over the passed channel's values skipping those seen before.
+// Every distinct value is kept, use DistinctApprox to bound the memory used.
+func Distinct[T comparable](c Chan[T]) Chan[T] {
+	return DistinctBy(c, func(val T) T { return val })
+}
+
+// DistinctBy iterates over the passed channel's values skipping those whose key,
+// returned by the passed function, was seen before. Every distinct key is kept.
+// The passed function is called once for each value.
+func DistinctBy[T any, K comparable](c Chan[T], key func(val T) K) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		seen := make(map[K]struct{})
+		for val := range each(s, c) {
+			k := key(val)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// DistinctUntilChanged iterates over the passed channel's values skipping those
+// equal to the value before them. Use DistinctUntilChangedBy for values that
+// are not comparable.
+func DistinctUntilChanged[T comparable](c Chan[T]) Chan[T] {
+	return c.DistinctUntilChangedBy(equal)
+}
+
+// DistinctUntilChangedBy iterates over main channel values skipping those equal
+// to the value before them. Values are compared using the passed function.
+func (c Chan[T]) DistinctUntilChangedBy(eq func(a, b T) bool) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		var last T
+		first := true
+		for val := range each(s, c) {
+			if !first && eq(last, val) {
+				continue
+			}
+			last, first = val, false
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// DistinctApprox iterates over the passed channel's values skipping those seen
+// before, using a Bloom filter of fixed size instead of keeping every value.
+// The filter is sized for n distinct values with a false positive rate of p, so
+// a value not seen before is skipped with probability p. The rate grows once
+// more than n distinct values have passed.
+func DistinctApprox[T comparable](c Chan[T], n int, p float64) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		filter := newBloom[T](n, p)
+		for val := range each(s, c) {
+			if filter.add(val) {
+				continue
+			}
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// bloom is a Bloom filter using double hashing to derive its hash functions.
+type bloom[T comparable] struct {
+	bits  []uint64
+	m     uint64
+	k     uint64
+	seeds [2]maphash.Seed
+}
+
+// newBloom creates a Bloom filter sized for n values with a false positive
+// rate of p.
+func newBloom[T comparable](n int, p float64) *bloom[T] {
+	n = max(n, 1)
+	p = min(max(p, math.SmallestNonzeroFloat64), 0.5)
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(max(math.Round(float64(m)/float64(n)*math.Ln2), 1))
+	return &bloom[T]{
+		bits:  make([]uint64, (m+63)/64),
+		m:     m,
+		k:     k,
+		seeds: [2]maphash.Seed{maphash.MakeSeed(), maphash.MakeSeed()},
+	}
+}
+
+// add adds the passed value to the filter. It returns true if the value was
+// probably added before.
+func (b *bloom[T]) add(val T) bool {
+	h1 := maphash.Comparable(b.seeds[0], val)
+	h2 := maphash.Comparable(b.seeds[1], val) | 1
+	seen := true
+	for i := range b.k {
+		bit := (h1 + i*h2) % b.m
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.bits[word]&mask == 0 {
+			seen = false
+			b.bits[word] |= mask
+		}
+	}
+	return seen
+}
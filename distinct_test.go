@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistinct(t *testing.T) {
+	expected := []int{3, 1, 4, 5, 9, 2, 6}
+	result := Distinct(FromSlice([]int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3})).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleDistinct() {
+	result := Distinct(FromString("a b a c b", " ")).Slice()
+
+	fmt.Println(result)
+	// Output: [a b c]
+}
+
+func TestDistinctBy(t *testing.T) {
+	expected := []string{"Lorem", "ipsum", "dolor"}
+	result := DistinctBy(FromString("Lorem ipsum dolor LOREM Dolor", " "), strings.ToLower).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestDistinctUntilChanged(t *testing.T) {
+	expected := []int{1, 2, 1, 3}
+	result := DistinctUntilChanged(FromSlice([]int{1, 1, 2, 2, 2, 1, 3, 3})).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestDistinctUntilChangedBy(t *testing.T) {
+	expected := [][]int{{1}, {2, 3}, {1}}
+	input := FromSlice([][]int{{1}, {1}, {2, 3}, {2, 3}, {1}})
+	result := input.DistinctUntilChangedBy(func(a, b []int) bool {
+		return fmt.Sprint(a) == fmt.Sprint(b)
+	}).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleDistinctUntilChanged() {
+	result := DistinctUntilChanged(FromSlice([]int{1, 1, 2, 2, 1})).Slice()
+
+	fmt.Println(result)
+	// Output: [1 2 1]
+}
+
+func TestDistinctApprox(t *testing.T) {
+	input := Iota(0, 10000, 1).Map(func(val int) int { return val % 1000 })
+	result := DistinctApprox(input, 1000, 0.01).Slice()
+
+	assert.LessOrEqual(t, len(result), 1000)
+	assert.Greater(t, len(result), 950)
+	assert.Len(t, Distinct(FromSlice(result)).Slice(), len(result))
+}
+
+func TestBloom(t *testing.T) {
+	filter := newBloom[int](100, 0.01)
+
+	assert.False(t, filter.add(1))
+	assert.True(t, filter.add(1))
+	assert.Equal(t, uint64(959), filter.m)
+	assert.Equal(t, uint64(7), filter.k)
+}
@@ -0,0 +1,139 @@
+package stream
+
+import (
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Encoder writes values of type T to a writer.
+type Encoder[T any] interface {
+	Encode(w io.Writer, val T) error
+}
+
+// EncoderFunc is a function used as an Encoder.
+type EncoderFunc[T any] func(w io.Writer, val T) error
+
+// Encode calls the function.
+func (f EncoderFunc[T]) Encode(w io.Writer, val T) error {
+	return f(w, val)
+}
+
+// WriteToWith writes the main channel values to the writer argument using the
+// passed encoder. If the main channel ended because of an error, that error is
+// returned once the channel's values have been written.
+func (c Chan[T]) WriteToWith(w io.Writer, enc Encoder[T]) error {
+	for val := range c {
+		if err := enc.Encode(w, val); err != nil {
+			return err
+		}
+	}
+	return c.Err()
+}
+
+// Raw returns the encoder used by WriteTo. Values are written as their bytes
+// in little endian byte order, int and uint values as 64 bit integers and
+// strings as their bytes, with nothing between values.
+func Raw[T any]() Encoder[T] {
+	return EncoderFunc[T](func(w io.Writer, val T) error {
+		return write(w, val)
+	})
+}
+
+// Binary returns an encoder writing values of fixed size types as their bytes
+// in the passed byte order. The int and uint types, and pointers to them, are
+// written as 64 bit integers. Strings are written as their bytes, use Framed to
+// tell them apart. Values of fixed size types can be read back using ReadBinary
+// with the same byte order.
+func Binary[T any](order binary.ByteOrder) Encoder[T] {
+	return EncoderFunc[T](func(w io.Writer, val T) error {
+		return writeBinary(w, order, val)
+	})
+}
+
+// writeBinary writes a single value as bytes in the passed byte order.
+func writeBinary(w io.Writer, order binary.ByteOrder, v any) error {
+	switch val := v.(type) {
+	case int:
+		return binary.Write(w, order, int64(val))
+	case *int:
+		return binary.Write(w, order, int64(*val))
+	case uint:
+		return binary.Write(w, order, uint64(val))
+	case *uint:
+		return binary.Write(w, order, uint64(*val))
+	case string:
+		_, err := io.WriteString(w, val)
+		return err
+	case *string:
+		_, err := io.WriteString(w, *val)
+		return err
+	default:
+		return binary.Write(w, order, val)
+	}
+}
+
+// Varint returns an encoder writing integers as varints, as by
+// binary.AppendVarint for signed and binary.AppendUvarint for unsigned types.
+func Varint[T Integer]() Encoder[T] {
+	signed := ^T(0) < 0
+	return EncoderFunc[T](func(w io.Writer, val T) error {
+		var buf []byte
+		if signed {
+			buf = binary.AppendVarint(nil, int64(val))
+		} else {
+			buf = binary.AppendUvarint(nil, uint64(val))
+		}
+		_, err := w.Write(buf)
+		return err
+	})
+}
+
+// Framed returns an encoder writing strings or byte slices preceded by their
+// length as a 32 bit integer in the passed byte order. They can be read back
+// using ReadFramed.
+func Framed[T ~string | ~[]byte](order binary.ByteOrder) Encoder[T] {
+	return EncoderFunc[T](func(w io.Writer, val T) error {
+		return writeFrame(w, order, []byte(val))
+	})
+}
+
+// Delimited returns an encoder writing the string representation of values,
+// each followed by the passed delimiter.
+func Delimited[T any](delim string) Encoder[T] {
+	return EncoderFunc[T](func(w io.Writer, val T) error {
+		_, err := fmt.Fprint(w, val, delim)
+		return err
+	})
+}
+
+// JSON returns an encoder writing values as JSON, each followed by a newline.
+func JSON[T any]() Encoder[T] {
+	return EncoderFunc[T](func(w io.Writer, val T) error {
+		return json.NewEncoder(w).Encode(val)
+	})
+}
+
+// gobEncoder keeps the gob.Encoder of the writer it last wrote to, as gob only
+// describes a type the first time it is sent on a stream.
+type gobEncoder[T any] struct {
+	w   io.Writer
+	enc *gob.Encoder
+}
+
+// Gob returns an encoder writing values using encoding/gob. The values written
+// to a writer form a single gob stream, to be read by one gob.Decoder. The
+// returned encoder must not be used concurrently.
+func Gob[T any]() Encoder[T] {
+	return &gobEncoder[T]{}
+}
+
+// Encode writes a value to the gob stream of the writer.
+func (e *gobEncoder[T]) Encode(w io.Writer, val T) error {
+	if e.enc == nil || e.w != w {
+		e.w, e.enc = w, gob.NewEncoder(w)
+	}
+	return e.enc.Encode(val)
+}
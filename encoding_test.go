@@ -0,0 +1,122 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteToWithRaw(t *testing.T) {
+	expected := new(bytes.Buffer)
+	assert.NoError(t, Iota(1, 3, 1).WriteTo(expected))
+
+	buf := new(bytes.Buffer)
+	err := Iota(1, 3, 1).WriteToWith(buf, Raw[int]())
+
+	assert.NoError(t, err)
+	assert.Equal(t, expected.Bytes(), buf.Bytes())
+}
+
+func TestWriteToWithBinary(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := FromSlice([]int{1, -1}).WriteToWith(buf, Binary[int](binary.BigEndian))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1, 255, 255, 255, 255, 255, 255, 255, 255}, buf.Bytes())
+}
+
+func TestWriteToWithBinaryIntPointer(t *testing.T) {
+	minus := -2
+
+	buf := new(bytes.Buffer)
+	err := FromSlice([]*int{&minus}).WriteToWith(buf, Binary[*int](binary.LittleEndian))
+
+	assert.NoError(t, err)
+	assert.Equal(t, []int{-2}, ReadBinary[int](buf, binary.LittleEndian).Slice())
+}
+
+func TestWriteToWithVarint(t *testing.T) {
+	buf := new(bytes.Buffer)
+	assert.NoError(t, FromSlice([]int{-1, 1, 300}).WriteToWith(buf, Varint[int]()))
+	assert.Equal(t, []byte{1, 2, 0xd8, 0x04}, buf.Bytes())
+
+	buf.Reset()
+	assert.NoError(t, FromSlice([]uint{1, 300}).WriteToWith(buf, Varint[uint]()))
+	assert.Equal(t, []byte{1, 0xac, 0x02}, buf.Bytes())
+}
+
+func TestWriteToWithFramed(t *testing.T) {
+	a := new(bytes.Buffer)
+	b := new(bytes.Buffer)
+
+	assert.NoError(t, FromSlice([]string{"ab", "c"}).WriteToWith(a, Framed[string](binary.LittleEndian)))
+	assert.NoError(t, FromSlice([]string{"a", "bc"}).WriteToWith(b, Framed[string](binary.LittleEndian)))
+
+	assert.NotEqual(t, a.Bytes(), b.Bytes())
+	assert.Equal(t, []string{"ab", "c"}, ReadFramed[string](a, binary.LittleEndian).Slice())
+}
+
+func TestWriteToWithDelimited(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := FromSlice([]string{"ab", "c"}).WriteToWith(buf, Delimited[string]("\x00"))
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ab\x00c\x00", buf.String())
+}
+
+func ExampleDelimited() {
+	Iota(1, 4, 1).WriteToWith(os.Stdout, Delimited[int](","))
+	// Output: 1,2,3,
+}
+
+func ExampleJSON() {
+	FromSlice([]Pair[string, int]{{"Lorem", 1}, {"ipsum", 2}}).WriteToWith(os.Stdout, JSON[Pair[string, int]]())
+	// Output:
+	// {"key":"Lorem","val":1}
+	// {"key":"ipsum","val":2}
+}
+
+func TestWriteToWithGob(t *testing.T) {
+	expected := []Pair[string, int]{{"Lorem", 1}, {"ipsum", 2}}
+
+	buf := new(bytes.Buffer)
+	err := FromSlice(expected).WriteToWith(buf, Gob[Pair[string, int]]())
+	assert.NoError(t, err)
+
+	result := make([]Pair[string, int], 0)
+	dec := gob.NewDecoder(buf)
+	for {
+		var val Pair[string, int]
+		if err := dec.Decode(&val); err == io.EOF {
+			break
+		} else {
+			assert.NoError(t, err)
+		}
+		result = append(result, val)
+	}
+	assert.Equal(t, expected, result)
+}
+
+func TestWriteToWithError(t *testing.T) {
+	failure := errors.New("failure")
+	enc := EncoderFunc[int](func(w io.Writer, val int) error {
+		if val == 2 {
+			return failure
+		}
+		_, err := fmt.Fprint(w, val)
+		return err
+	})
+
+	buf := new(bytes.Buffer)
+	err := Iota(1, 4, 1).WriteToWith(buf, enc)
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, "1", buf.String())
+}
@@ -9,13 +9,15 @@ import (
 
 // FromSlice creates a channel that will return the items in the passed slice.
 // The channel will close when the slice values are exhausted.
-func FromSlice[T comparable](slice []T) Chan[T] {
-	output := make(Chan[T])
+func FromSlice[T any](slice []T) Chan[T] {
+	s, output := spawn[Chan[T]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for _, e := range slice {
-			output <- e
+			if !send(s, output, e) {
+				return
+			}
 		}
 	}()
 
@@ -25,13 +27,15 @@ func FromSlice[T comparable](slice []T) Chan[T] {
 // Cycle creates a channel that will repeat the items in the passed slice
 // infinitely. This channel will not close by itself and should be limited using
 // other methods.
-func Cycle[T comparable](slice []T) Chan[T] {
-	output := make(Chan[T])
+func Cycle[T any](slice []T) Chan[T] {
+	s, output := spawn[Chan[T]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for i := 0; i < len(slice); i++ {
-			output <- slice[i]
+			if !send(s, output, slice[i]) {
+				return
+			}
 			if i == len(slice)-1 {
 				i = -1
 			}
@@ -44,13 +48,15 @@ func Cycle[T comparable](slice []T) Chan[T] {
 // Generate creates a channel that will return values returned from the passed
 // function. This channel will not close by itself and should be limited using
 // other methods.
-func Generate[T comparable](f func() T) Chan[T] {
-	output := make(Chan[T])
+func Generate[T any](f func() T) Chan[T] {
+	s, output := spawn[Chan[T]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
-			output <- f()
+			if !send(s, output, f()) {
+				return
+			}
 		}
 	}()
 
@@ -59,13 +65,15 @@ func Generate[T comparable](f func() T) Chan[T] {
 
 // Repeat creates a channel that will repeat the passed value infinitely. This
 // channel will not close by itself and should be limited using other methods.
-func Repeat[T comparable](val T) Chan[T] {
-	output := make(Chan[T])
+func Repeat[T any](val T) Chan[T] {
+	s, output := spawn[Chan[T]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
-			output <- val
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -74,13 +82,15 @@ func Repeat[T comparable](val T) Chan[T] {
 
 // FromChannel creates a channel that will return the values of the passed
 // channel. The channel will close when passed channel is closed.
-func FromChannel[T comparable](c <-chan T) Chan[T] {
-	output := make(Chan[T])
+func FromChannel[T any](c <-chan T) Chan[T] {
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			output <- val
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -90,12 +100,14 @@ func FromChannel[T comparable](c <-chan T) Chan[T] {
 // FromString creates a channel that will return strings delimited by a
 // separator. The channel will close when the strings are exhausted.
 func FromString(str string, sep string) Chan[string] {
-	output := make(Chan[string])
+	s, output := spawn[Chan[string]]()
 
 	go func() {
-		defer close(output)
-		for val := range FromSlice(strings.Split(str, sep)) {
-			output <- val
+		defer finish(s, output)
+		for _, val := range strings.Split(str, sep) {
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -105,12 +117,14 @@ func FromString(str string, sep string) Chan[string] {
 // FromRunes creates a channel that will return the runes in the string. The
 // channel will close when the runes are exhausted.
 func FromRunes(str string) Chan[rune] {
-	output := make(Chan[rune])
+	s, output := spawn[Chan[rune]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for _, r := range str {
-			output <- r
+			if !send(s, output, r) {
+				return
+			}
 		}
 	}()
 
@@ -118,19 +132,26 @@ func FromRunes(str string) Chan[rune] {
 }
 
 // FromReader creates a channel that will return the bytes read from the
-// io.Reader implementation.
+// io.Reader implementation. The channel will close when the reader returns an
+// error. Errors other than io.EOF are available from the channel's Err method
+// once it has closed.
 func FromReader(r io.Reader) Chan[byte] {
-	output := make(Chan[byte])
+	s, output := spawn[Chan[byte]]()
 	buffer := make([]byte, 4096) // Default page size.
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
 			n, err := r.Read(buffer)
 			for i := 0; i < n; i++ {
-				output <- buffer[i]
+				if !send(s, output, buffer[i]) {
+					return
+				}
 			}
 			if err != nil {
+				if err != io.EOF {
+					s.fail(err)
+				}
 				return
 			}
 		}
@@ -143,12 +164,14 @@ func FromReader(r io.Reader) Chan[byte] {
 // arguments. This channel will not close by itself and should be limited using
 // other methods.
 func Iota(start, end, step int) Chan[int] {
-	output := make(Chan[int])
+	s, output := spawn[Chan[int]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for i := start; i < end; i += step {
-			output <- i
+			if !send(s, output, i) {
+				return
+			}
 		}
 	}()
 
@@ -158,14 +181,16 @@ func Iota(start, end, step int) Chan[int] {
 // Fibonacci creates an integer channel returning the fibonacci sequence. This
 // channel will not close by itself and should be limited using other methods.
 func Fibonacci() Chan[*big.Int] {
-	output := make(Chan[*big.Int])
+	s, output := spawn[Chan[*big.Int]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		a := big.NewInt(0)
 		b := big.NewInt(1)
 		for {
-			output <- big.NewInt(0).Set(a.Add(a, b))
+			if !send(s, output, big.NewInt(0).Set(a.Add(a, b))) {
+				return
+			}
 			a, b = b, a
 		}
 	}()
@@ -177,10 +202,13 @@ func Fibonacci() Chan[*big.Int] {
 // close when the sequence exceeds the returned channel's type limits which may
 // take a long time.
 func Primes() Chan[int] {
-	output := make(Chan[int])
+	s, output := spawn[Chan[int]]()
 
 	go func() {
-		output <- 2
+		defer finish(s, output)
+		if !send(s, output, 2) {
+			return
+		}
 		primes := make([]int, 0)
 		for n := 3; n > 0; n += 2 {
 			isPrime := true
@@ -194,7 +222,9 @@ func Primes() Chan[int] {
 				if n < 0 {
 					return
 				}
-				output <- n
+				if !send(s, output, n) {
+					return
+				}
 				primes = append(primes, n)
 			}
 		}
@@ -206,12 +236,14 @@ func Primes() Chan[int] {
 // RandInt creates an integer channel returning random integers. This channel
 // will not close by itself and should be limited using other methods.
 func RandInt() Chan[int] {
-	output := make(Chan[int])
+	s, output := spawn[Chan[int]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
-			output <- rand.Int()
+			if !send(s, output, rand.Int()) {
+				return
+			}
 		}
 	}()
 
@@ -221,12 +253,14 @@ func RandInt() Chan[int] {
 // RandFloat32 creates a (32bit) float channel returning random floats. This
 // channel will not close by itself and should be limited using other methods.
 func RandFloat32() Chan[float32] {
-	output := make(Chan[float32])
+	s, output := spawn[Chan[float32]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
-			output <- rand.Float32()
+			if !send(s, output, rand.Float32()) {
+				return
+			}
 		}
 	}()
 
@@ -236,12 +270,14 @@ func RandFloat32() Chan[float32] {
 // RandFloat64 creates a (64bit) float channel returning random floats. This
 // channel will not close by itself and should be limited using other methods.
 func RandFloat64() Chan[float64] {
-	output := make(Chan[float64])
+	s, output := spawn[Chan[float64]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
-			output <- rand.Float64()
+			if !send(s, output, rand.Float64()) {
+				return
+			}
 		}
 	}()
 
@@ -250,19 +286,25 @@ func RandFloat64() Chan[float64] {
 
 // ReadFrom creates a byte channel returning bytes read from the passed reader.
 // The channel will close when the reader returns an error. This error could be
-// a EOF indicating the data has been exhausted or any other error.
+// a EOF indicating the data has been exhausted or any other error. Errors other
+// than io.EOF are available from the channel's Err method once it has closed.
 func ReadFrom(r io.Reader) Chan[byte] {
-	output := make(Chan[byte])
+	s, output := spawn[Chan[byte]]()
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		buf := make([]byte, 8)
 		for {
 			n, err := r.Read(buf)
 			for i := 0; i < n; i++ {
-				output <- buf[i]
+				if !send(s, output, buf[i]) {
+					return
+				}
 			}
 			if err != nil {
+				if err != io.EOF {
+					s.fail(err)
+				}
 				return
 			}
 		}
@@ -2,9 +2,13 @@ package stream
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"math/big"
+	"strings"
 	"testing"
+	"testing/iotest"
 	"unicode"
 
 	"github.com/stretchr/testify/assert"
@@ -150,6 +154,27 @@ func TestFromReader(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestFromReaderError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem"), iotest.ErrReader(failure))
+
+	c := FromReader(r)
+	assert.Equal(t, []byte("Lorem"), c.Slice())
+	assert.Equal(t, failure, c.Err())
+
+	r = io.MultiReader(strings.NewReader("Lorem"), iotest.ErrReader(failure))
+	c = FromReader(r).Filter(func(val byte) bool { return val != 'e' })
+	assert.Equal(t, []byte("Lorm"), c.Slice())
+	assert.Equal(t, failure, c.Err())
+}
+
+func TestFromReaderEOF(t *testing.T) {
+	c := FromReader(strings.NewReader("Lorem"))
+
+	assert.Equal(t, []byte("Lorem"), c.Slice())
+	assert.NoError(t, c.Err())
+}
+
 func ExampleFromReader() {
 	r := bytes.NewBufferString("Lorem ipsum dolor sit amet")
 	result := FromReader(r).Take(11).Slice()
@@ -264,6 +289,15 @@ func TestReadFrom(t *testing.T) {
 	assert.Equal(t, expected, string(result))
 }
 
+func TestReadFromError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem ipsum"), iotest.ErrReader(failure))
+
+	c := ReadFrom(r)
+	assert.Equal(t, []byte("Lorem ipsum"), c.Slice())
+	assert.Equal(t, failure, c.Err())
+}
+
 func ExampleReadFrom() {
 	data := []byte("Lorem ipsum dolor sit amet")
 	r := bytes.NewReader(data)
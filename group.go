@@ -0,0 +1,177 @@
+package stream
+
+import (
+	"container/list"
+	"errors"
+	"time"
+)
+
+// ErrTooManyGroups is reported by the Err method of a channel returned by
+// GroupByWith when a value would open more groups than allowed.
+var ErrTooManyGroups = errors.New("stream: too many groups")
+
+// Grouping configures how GroupByWith splits values into groups.
+type Grouping struct {
+	// MaxGroups bounds the number of groups open at once. A value that would
+	// open another group ends the grouping with ErrTooManyGroups. Zero means
+	// no limit.
+	MaxGroups int
+
+	// Idle closes a group once no value has arrived for its key for this
+	// long. A later value with the same key opens a new group. Zero means
+	// groups stay open until the passed channel closes.
+	Idle time.Duration
+}
+
+// group is an open group of a grouping stage.
+type group[K comparable, T any] struct {
+	key   K
+	stage *stage
+	feed  chan T
+	out   Chan[T]
+	last  time.Time
+	elem  *list.Element
+}
+
+// GroupBy splits the passed channel's values into groups by the key returned
+// by the passed function. A pair holding the key and a channel of the group's
+// values is returned the first time a key is seen. Every group channel must be
+// read, as a value for a group that isn't read blocks the others. Within a
+// pipeline, a group whose channel is no longer read is closed and a later value
+// with its key opens a new group. The passed function is called once for each
+// value.
+func GroupBy[T any, K comparable](c Chan[T], key func(val T) K) Chan[Pair[K, Chan[T]]] {
+	return GroupByWith(c, Grouping{}, key)
+}
+
+// GroupByWith splits the passed channel's values into groups by the key
+// returned by the passed function, using the passed configuration. The passed
+// function is called once for each value.
+func GroupByWith[T any, K comparable](c Chan[T], config Grouping, key func(val T) K) Chan[Pair[K, Chan[T]]] {
+	s, output := spawn[Chan[Pair[K, Chan[T]]]](c)
+	clock := s.clock()
+
+	go func() {
+		groups := make(map[K]*group[K, T])
+		recent := list.New()
+		defer func() {
+			for _, g := range groups {
+				close(g.feed)
+			}
+		}()
+		defer finish(s, output)
+
+		closeGroup := func(g *group[K, T]) {
+			close(g.feed)
+			recent.Remove(g.elem)
+			delete(groups, g.key)
+		}
+
+		var timer Timer
+		var due <-chan time.Time
+		arm := func() {
+			if config.Idle <= 0 || due != nil || recent.Len() == 0 {
+				return
+			}
+			oldest := recent.Front().Value.(*group[K, T])
+			wait := oldest.last.Add(config.Idle).Sub(clock.Now())
+			if timer == nil {
+				timer = clock.NewTimer(wait)
+			} else {
+				timer.Reset(wait)
+			}
+			due = timer.C()
+		}
+		defer func() {
+			if timer != nil {
+				timer.Stop()
+			}
+		}()
+
+		for {
+			select {
+			case val, ok := <-c:
+				if !ok {
+					return
+				}
+				k := key(val)
+				for {
+					g, ok := groups[k]
+					if !ok {
+						if config.MaxGroups > 0 && len(groups) >= config.MaxGroups {
+							s.fail(ErrTooManyGroups)
+							return
+						}
+						g = openGroup[T](s, k)
+						g.elem = recent.PushBack(g)
+						groups[k] = g
+						if !send(s, output, Pair[K, Chan[T]]{Key: k, Val: g.out}) {
+							return
+						}
+					}
+					g.last = clock.Now()
+					recent.MoveToBack(g.elem)
+					arm()
+					select {
+					case g.feed <- val:
+					case <-g.stage.exited:
+						closeGroup(g)
+						continue
+					case <-s.stop:
+						return
+					}
+					break
+				}
+			case <-due:
+				due = nil
+				now := clock.Now()
+				for recent.Len() > 0 {
+					oldest := recent.Front().Value.(*group[K, T])
+					if oldest.last.Add(config.Idle).After(now) {
+						break
+					}
+					closeGroup(oldest)
+				}
+				arm()
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// openGroup starts the stage passing the values of a new group to its channel.
+// The stage joins the pipeline of the passed grouping stage and ends with its
+// error, but doesn't count as one of its readers, so the grouping stage's error
+// is kept for the grouped channel.
+func openGroup[T any, K comparable](parent *stage, key K) *group[K, T] {
+	s := newStage()
+	p := parent.bound()
+	capacity := 0
+	if p != nil {
+		capacity = p.capacity
+	}
+	out := makeOutput[Chan[T]](s, capacity)
+	if p != nil {
+		p.attach(s)
+	}
+	feed := make(chan T)
+
+	go func() {
+		defer finish(s, out)
+		defer func() {
+			if err := parent.error(); err != nil {
+				s.fail(err)
+			}
+		}()
+		for val := range each(s, feed) {
+			if !send(s, out, val) {
+				return
+			}
+		}
+	}()
+
+	return &group[K, T]{key: key, stage: s, feed: feed, out: out}
+}
@@ -0,0 +1,139 @@
+package stream_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nomad-software/stream"
+	"github.com/nomad-software/stream/streamtest"
+	"github.com/stretchr/testify/assert"
+)
+
+// collectGroups reads every group returned by the passed channel concurrently.
+func collectGroups[K comparable, T any](c stream.Chan[stream.Pair[K, stream.Chan[T]]]) map[K][]T {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	result := make(map[K][]T)
+	for pair := range c {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vals := pair.Val.Slice()
+			mu.Lock()
+			result[pair.Key] = append(result[pair.Key], vals...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return result
+}
+
+func TestGroupBy(t *testing.T) {
+	expected := map[byte][]string{
+		'l': {"Lorem"},
+		'i': {"ipsum"},
+		'd': {"dolor"},
+		's': {"sit"},
+		'a': {"amet", "adipiscing"},
+		'c': {"consectetur"},
+		'e': {"elit"},
+	}
+	words := stream.FromString("Lorem ipsum dolor sit amet consectetur adipiscing elit", " ")
+	c := stream.GroupBy(words, func(val string) byte {
+		return val[0] | 0x20
+	})
+
+	assert.Equal(t, expected, collectGroups(c))
+	assert.NoError(t, c.Err())
+}
+
+func ExampleGroupBy() {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	counts := make(map[bool]int)
+
+	even := func(val int) bool {
+		return val%2 == 0
+	}
+
+	for pair := range stream.GroupBy(stream.Iota(1, 10, 1), even) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n := pair.Val.Count()
+			mu.Lock()
+			counts[pair.Key] = n
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	fmt.Println(counts[true], counts[false])
+	// Output: 4 5
+}
+
+func TestGroupByMaxGroups(t *testing.T) {
+	c := stream.GroupByWith(stream.Iota(1, 10, 1), stream.Grouping{MaxGroups: 2}, func(val int) int {
+		return val / 4
+	})
+
+	result := collectGroups(c)
+	assert.Equal(t, map[int][]int{0: {1, 2, 3}, 1: {4, 5, 6, 7}}, result)
+	assert.ErrorIs(t, c.Err(), stream.ErrTooManyGroups)
+}
+
+func TestGroupByIdle(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.GroupByWith(stream.Chan[int](input).In(p), stream.Grouping{Idle: time.Second}, func(val int) bool {
+		return val%2 == 0
+	})
+
+	input <- 1
+	odd := <-c
+	assert.False(t, odd.Key)
+	assert.Equal(t, 1, <-odd.Val)
+
+	clock.WaitArmed(1)
+	clock.Advance(500 * time.Millisecond)
+	input <- 2
+	even := <-c
+	assert.True(t, even.Key)
+	assert.Equal(t, 2, <-even.Val)
+
+	clock.Advance(500 * time.Millisecond)
+	_, ok := <-odd.Val
+	assert.False(t, ok)
+
+	clock.WaitArmed(2)
+	input <- 3
+	odd = <-c
+	assert.False(t, odd.Key)
+	assert.Equal(t, 3, <-odd.Val)
+
+	close(input)
+	assert.Equal(t, []int{}, even.Val.Slice())
+	assert.Equal(t, []int{}, odd.Val.Slice())
+	assert.Equal(t, 0, c.Count())
+}
+
+func TestGroupByUnwinds(t *testing.T) {
+	p := stream.NewPipeline()
+	defer p.Stop()
+
+	c := stream.GroupBy(stream.Iota(0, 1000000, 1).In(p), func(val int) int {
+		return val % 3
+	})
+
+	for pair := range c.Take(3) {
+		go pair.Val.Take(2).Slice()
+	}
+
+	p.Wait()
+	assert.NoError(t, p.Err())
+}
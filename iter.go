@@ -0,0 +1,94 @@
+package stream
+
+import "iter"
+
+// Pair holds a key and a value yielded by an iter.Seq2.
+type Pair[K, V any] struct {
+	Key K `json:"key"`
+	Val V `json:"val"`
+}
+
+// FromSeq creates a channel that will return the values yielded by the passed
+// iterator. The channel will close when the iterator is exhausted.
+func FromSeq[T any](seq iter.Seq[T]) Chan[T] {
+	s, output := spawn[Chan[T]]()
+
+	go func() {
+		defer finish(s, output)
+		for val := range seq {
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// FromSeq2 creates a channel that will return the pairs yielded by the passed
+// iterator. The channel will close when the iterator is exhausted.
+func FromSeq2[K, V any](seq iter.Seq2[K, V]) Chan[Pair[K, V]] {
+	s, output := spawn[Chan[Pair[K, V]]]()
+
+	go func() {
+		defer finish(s, output)
+		for key, val := range seq {
+			if !send(s, output, Pair[K, V]{Key: key, Val: val}) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// All returns an iterator over the main channel values. If the loop using the
+// iterator breaks early, the stages feeding the main channel are stopped,
+// unless something else still reads from them.
+func (c Chan[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for val := range c {
+			if !yield(val) {
+				abandon(c)
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the main channel's indexes and values. If the
+// loop using the iterator breaks early, the stages feeding the main channel are
+// stopped, unless something else still reads from them.
+func (c ChanEnum[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for enum := range c {
+			if !yield(enum.Index, enum.Val) {
+				abandon(c)
+				return
+			}
+		}
+	}
+}
+
+// AllPairs returns an iterator over the keys and values of the passed channel.
+// If the loop using the iterator breaks early, the stages feeding the channel
+// are stopped, unless something else still reads from them.
+func AllPairs[K, V any](c Chan[Pair[K, V]]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for pair := range c {
+			if !yield(pair.Key, pair.Val) {
+				abandon(c)
+				return
+			}
+		}
+	}
+}
+
+// abandon stops the stage feeding the passed channel once nothing is going to
+// read from it again. The stages feeding it are stopped in turn once they have
+// no readers left, stages still read by others carry on.
+func abandon(c any) {
+	if s := lookup(c); s != nil {
+		s.unwind()
+	}
+}
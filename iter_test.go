@@ -0,0 +1,146 @@
+package stream
+
+import (
+	"fmt"
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromSeq(t *testing.T) {
+	expected := []string{"Lorem", "ipsum", "dolor"}
+	result := FromSeq(strings.FieldsSeq("Lorem ipsum dolor")).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleFromSeq() {
+	result := FromSeq(slices.Values([]int{3, 1, 2})).Map(func(val int) int {
+		return val * 10
+	}).Slice()
+
+	fmt.Println(result)
+	// Output: [30 10 20]
+}
+
+func TestFromSeq2(t *testing.T) {
+	expected := map[string]int{"Lorem": 5, "ipsum": 5}
+	m := map[string]int{"Lorem": 5, "ipsum": 5}
+
+	result := maps.Collect(AllPairs(FromSeq2(maps.All(m))))
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleFromSeq2() {
+	words := []string{"Lorem", "ipsum", "dolor"}
+
+	for pair := range FromSeq2(slices.All(words)) {
+		fmt.Println(pair.Key, pair.Val)
+	}
+	// Output:
+	// 0 Lorem
+	// 1 ipsum
+	// 2 dolor
+}
+
+func TestAll(t *testing.T) {
+	expected := []int{1, 2, 3, 4}
+	result := slices.Collect(Iota(1, 5, 1).All())
+
+	assert.Equal(t, expected, result)
+}
+
+func TestAllBreak(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	for val := range Repeat(1).Map(func(val int) int { return val + 1 }).All() {
+		assert.Equal(t, 2, val)
+		break
+	}
+
+	assertGoroutines(t, before)
+}
+
+func TestAllBreakPipeline(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	for range RandInt().In(p).Filter(func(val int) bool { return true }).All() {
+		break
+	}
+
+	p.Wait()
+}
+
+func TestAllBreakErr(t *testing.T) {
+	c := Repeat(1).Map(func(val int) int { return val + 1 })
+	for range c.All() {
+		break
+	}
+
+	c.Drain()
+	assert.NoError(t, c.Err())
+}
+
+func TestAllBreakShared(t *testing.T) {
+	fanOuts := map[string]func(c Chan[int]) []Chan[int]{
+		"Broadcast": func(c Chan[int]) []Chan[int] { return c.Broadcast(2) },
+		"Balance":   func(c Chan[int]) []Chan[int] { return c.Balance(2) },
+	}
+
+	for name, fanOut := range fanOuts {
+		t.Run(name, func(t *testing.T) {
+			subs := fanOut(Iota(0, 100, 1))
+			sibling := make(chan []int)
+			go func() { sibling <- subs[1].Slice() }()
+
+			for range subs[0].All() {
+				break
+			}
+
+			result := <-sibling
+			assert.GreaterOrEqual(t, len(result), 98)
+			assert.Equal(t, 99, result[len(result)-1])
+			assert.NoError(t, subs[1].Err())
+		})
+	}
+}
+
+func ExampleChan_All() {
+	for val := range Primes().All() {
+		if val > 10 {
+			break
+		}
+		fmt.Println(val)
+	}
+	// Output:
+	// 2
+	// 3
+	// 5
+	// 7
+}
+
+func TestChanEnumAll(t *testing.T) {
+	expected := map[int]string{1: "Lorem", 2: "ipsum"}
+	result := maps.Collect(FromString("Lorem ipsum", " ").Enumerate(1).All())
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleChanEnum_All() {
+	for i, val := range Cycle([]string{"Lorem", "ipsum"}).Enumerate(0).All() {
+		if i == 3 {
+			break
+		}
+		fmt.Println(i, val)
+	}
+	// Output:
+	// 0 Lorem
+	// 1 ipsum
+	// 2 Lorem
+}
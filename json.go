@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// FromJSONLines creates a channel that will return the values decoded from the
+// JSON Lines read from the io.Reader implementation, one value per line. Blank
+// lines are ignored. Lines that can't be decoded are handled by the passed
+// policy, a dead letter policy receiving them as a Failure[string]. The channel
+// will close when the reader is exhausted. Errors reading the lines are
+// available from the channel's Err method once it has closed.
+func FromJSONLines[T any](r io.Reader, policy ErrPolicy) Chan[T] {
+	checkPolicy[string](policy)
+	s, output := spawn[Chan[T]]()
+	reader := bufio.NewReader(r)
+
+	go func() {
+		defer finish(s, output)
+		for n := 1; ; n++ {
+			line, err := reader.ReadBytes('\n')
+			if err != nil && err != io.EOF {
+				s.fail(err)
+				return
+			}
+			if trimmed := bytes.TrimSpace(line); len(trimmed) > 0 {
+				var val T
+				if jsonErr := json.Unmarshal(trimmed, &val); jsonErr != nil {
+					jsonErr = fmt.Errorf("stream: line %d: %w", n, jsonErr)
+					if !handle(s, policy, string(trimmed), jsonErr) {
+						return
+					}
+				} else if !send(s, output, val) {
+					return
+				}
+			}
+			if err == io.EOF {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// WriteJSONLines writes the main channel values to the writer argument as JSON
+// Lines, one value per line. If the main channel ended because of an error,
+// that error is returned once the channel's values have been written.
+func (c Chan[T]) WriteJSONLines(w io.Writer) error {
+	buffer := bufio.NewWriter(w)
+	encoder := json.NewEncoder(buffer)
+	for val := range c {
+		if err := encoder.Encode(val); err != nil {
+			// The lines before the value are still written.
+			buffer.Flush()
+			return err
+		}
+	}
+	if err := buffer.Flush(); err != nil {
+		return err
+	}
+	return c.Err()
+}
@@ -0,0 +1,121 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type event struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+const events = `{"name":"Lorem","count":1}
+
+{"name":"ipsum","count":2}
+{"name":"dolor",
+{"name":"sit","count":4}
+`
+
+func TestFromJSONLines(t *testing.T) {
+	expected := []event{{"Lorem", 1}, {"ipsum", 2}}
+	c := FromJSONLines[event](strings.NewReader(events), StopOnError)
+
+	assert.Equal(t, expected, c.Slice())
+
+	var syntaxErr *json.SyntaxError
+	assert.ErrorAs(t, c.Err(), &syntaxErr)
+	assert.ErrorContains(t, c.Err(), "line 4")
+}
+
+func TestFromJSONLinesSkip(t *testing.T) {
+	expected := []event{{"Lorem", 1}, {"ipsum", 2}, {"sit", 4}}
+	c := FromJSONLines[event](strings.NewReader(events), SkipOnError)
+
+	assert.Equal(t, expected, c.Slice())
+	assert.NoError(t, c.Err())
+	assert.ErrorContains(t, c.Skipped(), "line 4")
+}
+
+func TestFromJSONLinesDeadLetter(t *testing.T) {
+	failed := make(chan Failure[string], 1)
+	c := FromJSONLines[event](strings.NewReader(events), DeadLetter(failed))
+
+	assert.Equal(t, []event{{"Lorem", 1}, {"ipsum", 2}, {"sit", 4}}, c.Slice())
+	assert.NoError(t, c.Err())
+
+	failure := <-failed
+	assert.Equal(t, `{"name":"dolor",`, failure.Val)
+	assert.Error(t, failure.Err)
+}
+
+func TestFromJSONLinesDeadLetterType(t *testing.T) {
+	failed := make(chan Failure[event])
+
+	assert.Panics(t, func() {
+		FromJSONLines[event](strings.NewReader(events), DeadLetter(failed))
+	})
+}
+
+func TestFromJSONLinesReadError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("1\n2\n"), iotest.ErrReader(failure))
+	c := FromJSONLines[int](r, StopOnError)
+
+	assert.Equal(t, []int{1, 2}, c.Slice())
+	assert.Equal(t, failure, c.Err())
+}
+
+func ExampleFromJSONLines() {
+	r := strings.NewReader("{\"name\":\"Lorem\",\"count\":1}\n{\"name\":\"ipsum\",\"count\":2}\n")
+
+	for e := range FromJSONLines[event](r, StopOnError) {
+		fmt.Println(e.Name, e.Count)
+	}
+	// Output:
+	// Lorem 1
+	// ipsum 2
+}
+
+func TestWriteJSONLines(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := FromSlice([]event{{"Lorem", 1}, {"ipsum", 2}}).WriteJSONLines(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"Lorem\",\"count\":1}\n{\"name\":\"ipsum\",\"count\":2}\n", buf.String())
+
+	result := FromJSONLines[event](buf, StopOnError).Slice()
+	assert.Equal(t, []event{{"Lorem", 1}, {"ipsum", 2}}, result)
+}
+
+func TestWriteJSONLinesError(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := FromSlice([]func(){func() {}}).WriteJSONLines(buf)
+
+	var typeErr *json.UnsupportedTypeError
+	assert.ErrorAs(t, err, &typeErr)
+
+	buf.Reset()
+	err = FromSlice([]float64{1, 2, math.NaN(), 4}).WriteJSONLines(buf)
+
+	var valueErr *json.UnsupportedValueError
+	assert.ErrorAs(t, err, &valueErr)
+	assert.Equal(t, "1\n2\n", buf.String())
+}
+
+func ExampleChan_WriteJSONLines() {
+	FromSlice([]Pair[string, int]{{"Lorem", 1}, {"ipsum", 2}}).WriteJSONLines(os.Stdout)
+	// Output:
+	// {"key":"Lorem","val":1}
+	// {"key":"ipsum","val":2}
+}
@@ -0,0 +1,123 @@
+package stream
+
+import (
+	"cmp"
+	"math"
+)
+
+// Integer is a constraint matching the integer types.
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// Number is a constraint matching the integer and floating point types.
+type Number interface {
+	Integer | ~float32 | ~float64
+}
+
+// Stats summarises numeric values. The variance is the population variance,
+// calculated using Welford's algorithm so it stays accurate over long streams.
+type Stats[T Number] struct {
+	Count    int     `json:"count"`
+	Sum      T       `json:"sum"`
+	Min      T       `json:"min"`
+	Max      T       `json:"max"`
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	StdDev   float64 `json:"stddev"`
+}
+
+// Add returns the summary updated with the passed value. It can be passed to
+// ScanTo or ReduceTo as the method expression Stats[T].Add.
+func (s Stats[T]) Add(val T) Stats[T] {
+	m2 := s.Variance * float64(s.Count)
+	if s.Count == 0 || val < s.Min {
+		s.Min = val
+	}
+	if s.Count == 0 || val > s.Max {
+		s.Max = val
+	}
+	s.Count++
+	s.Sum += val
+	delta := float64(val) - s.Mean
+	s.Mean += delta / float64(s.Count)
+	m2 += delta * (float64(val) - s.Mean)
+	s.Variance = m2 / float64(s.Count)
+	s.StdDev = math.Sqrt(s.Variance)
+	return s
+}
+
+// Summarize returns the summary of the passed channel's values once it closes.
+// The summary's Count is zero if the channel is empty.
+func Summarize[T Number](c Chan[T]) Stats[T] {
+	return ReduceTo(c, Stats[T]{}, Stats[T].Add).Pop()
+}
+
+// RunningStats passes on the summary of the passed channel's values so far,
+// once for each value.
+func RunningStats[T Number](c Chan[T]) Chan[Stats[T]] {
+	return ScanTo(c, Stats[T]{}, Stats[T].Add)
+}
+
+// Sum returns the sum of the passed channel's values once it closes.
+func Sum[T Number](c Chan[T]) T {
+	var sum T
+	for val := range c {
+		sum += val
+	}
+	return sum
+}
+
+// Min returns the smallest of the passed channel's values once it closes. It
+// returns false if the channel is empty.
+func Min[T cmp.Ordered](c Chan[T]) (T, bool) {
+	least, ok := <-c
+	for val := range c {
+		least = min(least, val)
+	}
+	return least, ok
+}
+
+// Max returns the largest of the passed channel's values once it closes. It
+// returns false if the channel is empty.
+func Max[T cmp.Ordered](c Chan[T]) (T, bool) {
+	most, ok := <-c
+	for val := range c {
+		most = max(most, val)
+	}
+	return most, ok
+}
+
+// MinMax returns the smallest and largest of the passed channel's values once
+// it closes. It returns false if the channel is empty.
+func MinMax[T cmp.Ordered](c Chan[T]) (T, T, bool) {
+	least, ok := <-c
+	most := least
+	for val := range c {
+		least = min(least, val)
+		most = max(most, val)
+	}
+	return least, most, ok
+}
+
+// Mean returns the arithmetic mean of the passed channel's values once it
+// closes. It returns false if the channel is empty.
+func Mean[T Number](c Chan[T]) (float64, bool) {
+	s := Summarize(c)
+	return s.Mean, s.Count > 0
+}
+
+// Variance returns the population variance of the passed channel's values once
+// it closes. It returns false if the channel is empty.
+func Variance[T Number](c Chan[T]) (float64, bool) {
+	s := Summarize(c)
+	return s.Variance, s.Count > 0
+}
+
+// StdDev returns the population standard deviation of the passed channel's
+// values once it closes. It returns false if the channel is empty.
+func StdDev[T Number](c Chan[T]) (float64, bool) {
+	s := Summarize(c)
+	return s.StdDev, s.Count > 0
+}
@@ -0,0 +1,119 @@
+package stream
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	result := Summarize(FromSlice([]int{2, 4, 4, 4, 5, 5, 7, 9}))
+
+	assert.Equal(t, 8, result.Count)
+	assert.Equal(t, 40, result.Sum)
+	assert.Equal(t, 2, result.Min)
+	assert.Equal(t, 9, result.Max)
+	assert.InDelta(t, 5.0, result.Mean, 1e-9)
+	assert.InDelta(t, 4.0, result.Variance, 1e-9)
+	assert.InDelta(t, 2.0, result.StdDev, 1e-9)
+}
+
+func TestSummarizeEmpty(t *testing.T) {
+	assert.Equal(t, Stats[float64]{}, Summarize(FromSlice([]float64{})))
+}
+
+func TestRunningStats(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	result := RunningStats(Iota(1, 100, 1).In(p)).Take(3).Slice()
+
+	assert.Len(t, result, 3)
+	assert.Equal(t, 3, result[2].Count)
+	assert.Equal(t, 6, result[2].Sum)
+	assert.Equal(t, 1, result[2].Min)
+	assert.Equal(t, 3, result[2].Max)
+	assert.InDelta(t, 2.0, result[2].Mean, 1e-9)
+	assert.InDelta(t, 2.0/3.0, result[2].Variance, 1e-9)
+}
+
+func ExampleRunningStats() {
+	for s := range RunningStats(FromSlice([]float64{1, 3, 5})) {
+		fmt.Println(s.Count, s.Mean)
+	}
+	// Output:
+	// 1 1
+	// 2 2
+	// 3 3
+}
+
+func TestSum(t *testing.T) {
+	assert.Equal(t, 45, Sum(Iota(1, 10, 1)))
+	assert.Equal(t, 0, Sum(FromSlice([]int{})))
+	assert.InDelta(t, 1.5, Sum(FromSlice([]float64{0.5, 1})), 1e-9)
+}
+
+func TestMin(t *testing.T) {
+	result, ok := Min(FromSlice([]int{3, 1, 4}))
+	assert.True(t, ok)
+	assert.Equal(t, 1, result)
+
+	_, ok = Min(FromSlice([]int{}))
+	assert.False(t, ok)
+}
+
+func TestMax(t *testing.T) {
+	result, ok := Max(FromString("Lorem ipsum dolor", " "))
+	assert.True(t, ok)
+	assert.Equal(t, "ipsum", result)
+
+	_, ok = Max(FromSlice([]string{}))
+	assert.False(t, ok)
+}
+
+func TestMinMax(t *testing.T) {
+	least, most, ok := MinMax(FromSlice([]int{3, 1, 4, 1, 5}))
+	assert.True(t, ok)
+	assert.Equal(t, 1, least)
+	assert.Equal(t, 5, most)
+
+	_, _, ok = MinMax(FromSlice([]int{}))
+	assert.False(t, ok)
+}
+
+func ExampleMinMax() {
+	least, most, _ := MinMax(Iota(1, 10, 1))
+
+	fmt.Println(least, most)
+	// Output: 1 9
+}
+
+func TestMean(t *testing.T) {
+	result, ok := Mean(Iota(1, 5, 1))
+	assert.True(t, ok)
+	assert.InDelta(t, 2.5, result, 1e-9)
+
+	_, ok = Mean(FromSlice([]int{}))
+	assert.False(t, ok)
+}
+
+func TestVariance(t *testing.T) {
+	variance, ok := Variance(FromSlice([]float64{1, 2, 3, 4}))
+	assert.True(t, ok)
+	assert.InDelta(t, 1.25, variance, 1e-9)
+
+	stddev, ok := StdDev(FromSlice([]float64{1, 2, 3, 4}))
+	assert.True(t, ok)
+	assert.InDelta(t, 1.118033988, stddev, 1e-9)
+
+	_, ok = StdDev(FromSlice([]float64{}))
+	assert.False(t, ok)
+}
+
+func ExampleStdDev() {
+	result, _ := StdDev(FromSlice([]int{2, 4, 4, 4, 5, 5, 7, 9}))
+
+	fmt.Println(result)
+	// Output: 2
+}
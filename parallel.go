@@ -0,0 +1,131 @@
+package stream
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// Parallel configures how values are spread across goroutines by parallel
+// operators.
+type Parallel struct {
+	// Workers is the number of goroutines calling the passed function. It
+	// defaults to GOMAXPROCS.
+	Workers int
+
+	// InFlight bounds the number of values read from the main channel that
+	// have not yet been sent on, including those held back to restore their
+	// order. It defaults to twice the number of workers and is never less
+	// than the number of workers.
+	InFlight int
+
+	// Unordered sends values on as soon as they are processed instead of in
+	// the order they were read.
+	Unordered bool
+}
+
+// ParallelMap mutates main channel values based on the passed function using
+// the passed number of goroutines. Values are returned in the order they were
+// read. The passed function is called once for each value and must be safe to
+// call concurrently.
+func (c Chan[T]) ParallelMap(workers int, f func(val T) T) Chan[T] {
+	return parallelMap(c, Parallel{Workers: workers}, f)
+}
+
+// ParallelMapWith mutates main channel values based on the passed function
+// using the passed configuration. The passed function is called once for each
+// value and must be safe to call concurrently.
+func (c Chan[T]) ParallelMapWith(config Parallel, f func(val T) T) Chan[T] {
+	return parallelMap(c, config, f)
+}
+
+// sequenced tags a value with the position it was read at.
+type sequenced[T any] struct {
+	seq int
+	val T
+}
+
+func parallelMap[T, U any](c Chan[T], config Parallel, f func(val T) U) Chan[U] {
+	workers := config.Workers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	inFlight := config.InFlight
+	if inFlight <= 0 {
+		inFlight = workers * 2
+	}
+	inFlight = max(inFlight, workers)
+
+	s, output := spawn[Chan[U]](c)
+	tokens := make(chan struct{}, inFlight)
+	jobs := make(chan sequenced[T])
+	results := make(chan sequenced[U])
+
+	var wg sync.WaitGroup
+	wg.Add(workers + 1)
+
+	go func() {
+		defer wg.Done()
+		defer close(jobs)
+		seq := 0
+		for val := range each(s, c) {
+			if !send(s, tokens, struct{}{}) {
+				return
+			}
+			if !send(s, jobs, sequenced[T]{seq: seq, val: val}) {
+				return
+			}
+			seq++
+		}
+	}()
+
+	var remaining atomic.Int32
+	remaining.Store(int32(workers))
+
+	for range workers {
+		go func() {
+			defer wg.Done()
+			defer func() {
+				if remaining.Add(-1) == 0 {
+					close(results)
+				}
+			}()
+			for job := range each(s, jobs) {
+				if !send(s, results, sequenced[U]{seq: job.seq, val: f(job.val)}) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer finish(s, output)
+		defer wg.Wait()
+		pending := make(map[int]U)
+		next := 0
+		for res := range each(s, results) {
+			if config.Unordered {
+				if !send(s, output, res.val) {
+					return
+				}
+				<-tokens
+				continue
+			}
+			pending[res.seq] = res.val
+			for {
+				val, ok := pending[next]
+				if !ok {
+					break
+				}
+				if !send(s, output, val) {
+					return
+				}
+				delete(pending, next)
+				<-tokens
+				next++
+			}
+		}
+	}()
+
+	return output
+}
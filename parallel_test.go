@@ -0,0 +1,91 @@
+package stream
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParallelMap(t *testing.T) {
+	expected := Iota(0, 1000, 1).Map(func(val int) int { return val * val }).Slice()
+	result := Iota(0, 1000, 1).ParallelMap(8, func(val int) int { return val * val }).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestParallelMapConcurrent(t *testing.T) {
+	var barrier sync.WaitGroup
+	barrier.Add(4)
+
+	result := Iota(0, 4, 1).ParallelMap(4, func(val int) int {
+		barrier.Done()
+		barrier.Wait()
+		return val
+	}).Slice()
+
+	assert.Equal(t, []int{0, 1, 2, 3}, result)
+}
+
+func TestParallelMapUnordered(t *testing.T) {
+	config := Parallel{Workers: 4, Unordered: true}
+	result := Iota(0, 100, 1).ParallelMapWith(config, func(val int) int {
+		if val%10 == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		return val
+	}).Slice()
+
+	assert.ElementsMatch(t, Iota(0, 100, 1).Slice(), result)
+}
+
+func TestParallelMapInFlight(t *testing.T) {
+	var read atomic.Int32
+	config := Parallel{Workers: 2, InFlight: 6}
+
+	c := Iota(0, 50, 1).
+		Tee(func(val int) { read.Add(1) }).
+		ParallelMapWith(config, func(val int) int { return val })
+
+	time.Sleep(20 * time.Millisecond)
+
+	// One value is held by the dispatcher and one by the Tee stage.
+	assert.LessOrEqual(t, read.Load(), int32(config.InFlight+2))
+	assert.Len(t, c.Slice(), 50)
+}
+
+func TestParallelMapStop(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	p := NewPipeline()
+	c := Repeat(2).In(p).ParallelMap(4, func(val int) int { return val * 2 })
+
+	assert.Equal(t, 4, <-c)
+	p.Stop()
+
+	assertGoroutines(t, before)
+}
+
+func ExampleChan_ParallelMap() {
+	result := Iota(1, 10, 1).ParallelMap(4, func(val int) int {
+		return val * val
+	}).Slice()
+
+	fmt.Println(result)
+	// Output: [1 4 9 16 25 36 49 64 81]
+}
+
+func ExampleChan_ParallelMapWith() {
+	config := Parallel{Workers: 4, Unordered: true}
+
+	result := Iota(1, 10, 1).ParallelMapWith(config, func(val int) int {
+		return val * val
+	}).Reduce(func(a, b int) int { return a + b }).Pop()
+
+	fmt.Println(result)
+	// Output: 285
+}
@@ -0,0 +1,242 @@
+package stream
+
+import (
+	"context"
+	"sync"
+)
+
+// Pipeline groups the stages of one or more chains so they can be cancelled
+// together. Channels are bound to a pipeline using In and every stage created
+// from a bound channel joins the same pipeline.
+//
+// Within a pipeline, a stage is stopped once every stage reading from it has
+// exited. This means short-circuiting operators such as Take or Until unwind
+// the stages behind them when they finish.
+type Pipeline struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	release func() bool
+	mu      sync.Mutex
+	stages  map[*stage]struct{}
+	stopped bool
+	err     error
+	skips   SkipError
+
+	capacity int
+	clock    Clock
+}
+
+// Option configures a pipeline.
+type Option func(p *Pipeline)
+
+// Capacity sets the capacity of the output channels of stages created in the
+// pipeline. Stages created before their channels are bound to the pipeline are
+// unbuffered, Buffer can be used to buffer those.
+func Capacity(n int) Option {
+	return func(p *Pipeline) {
+		p.capacity = n
+	}
+}
+
+// NewPipeline creates a pipeline that is only cancelled by calling Stop.
+func NewPipeline(opts ...Option) *Pipeline {
+	return WithContext(context.Background(), opts...)
+}
+
+// WithContext creates a pipeline that is cancelled when the passed context is
+// done. Once cancelled, every stage of the pipeline exits and closes its output
+// channel.
+func WithContext(ctx context.Context, opts ...Option) *Pipeline {
+	p := &Pipeline{stages: make(map[*stage]struct{}), clock: SystemClock}
+	for _, opt := range opts {
+		opt(p)
+	}
+	p.ctx, p.cancel = context.WithCancel(ctx)
+	p.release = context.AfterFunc(p.ctx, p.teardown)
+	return p
+}
+
+// Context returns the context of the pipeline.
+func (p *Pipeline) Context() context.Context {
+	return p.ctx
+}
+
+// Stop cancels the pipeline and waits for all of its stages to exit. Stop
+// should always be called once the pipeline is no longer needed to release the
+// resources associated with its stages. Errors remain available from the
+// pipeline's Err method.
+func (p *Pipeline) Stop() {
+	pending := p.release()
+	p.cancel()
+	if pending {
+		p.teardown()
+	}
+	p.Wait()
+}
+
+// Err returns the first error reported by a stage of the pipeline. If the
+// pipeline was cancelled before its stages finished, the cause of the
+// cancellation is returned.
+func (p *Pipeline) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// fail records the passed error. Only the first error is kept.
+func (p *Pipeline) fail(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.err == nil {
+		p.err = err
+	}
+}
+
+// Skipped returns a *SkipError reporting the values dropped by SkipOnError in
+// every stage of the pipeline, or nil if none were.
+func (p *Pipeline) Skipped() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return errSkipped(p.skips)
+}
+
+// skip records n values dropped by SkipOnError, the first because of the
+// passed error.
+func (p *Pipeline) skip(n int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.skips.add(n, err)
+}
+
+// Wait blocks until every stage of the pipeline has exited. Stages fed by
+// infinite generators only exit once the pipeline is stopped, or once every
+// stage reading from them has exited.
+func (p *Pipeline) Wait() {
+	for {
+		var s *stage
+		p.mu.Lock()
+		for s = range p.stages {
+			break
+		}
+		p.mu.Unlock()
+		if s == nil {
+			return
+		}
+		<-s.exited
+		p.remove(s)
+	}
+}
+
+// remove removes an exited stage from the pipeline, so the pipeline only holds
+// the stages still running.
+func (p *Pipeline) remove(s *stage) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.stages, s)
+}
+
+// attach adds the passed stage and all stages upstream of it to the pipeline.
+// Stages already belonging to a pipeline are left alone.
+func (p *Pipeline) attach(s *stage) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	if s.pipeline != nil {
+		s.mu.Unlock()
+		return
+	}
+	s.pipeline = p
+	orphaned := s.orphaned
+	err := s.err
+	skips := s.skips
+	s.mu.Unlock()
+
+	if err != nil {
+		p.fail(err)
+	}
+	p.skip(skips.Count, skips.Err)
+
+	// The context may be done before teardown has run, so it is checked too.
+	p.mu.Lock()
+	stopped := p.stopped || p.ctx.Err() != nil
+	p.stages[s] = struct{}{}
+	p.mu.Unlock()
+
+	// The stage may have exited before it was added.
+	select {
+	case <-s.exited:
+		p.remove(s)
+	default:
+	}
+
+	if stopped {
+		s.halt(context.Cause(p.ctx))
+	} else if orphaned {
+		s.halt(nil)
+	}
+
+	for _, u := range s.upstream {
+		p.attach(u)
+	}
+}
+
+// teardown stops every stage of the pipeline.
+func (p *Pipeline) teardown() {
+	p.mu.Lock()
+	p.stopped = true
+	bound := make([]*stage, 0, len(p.stages))
+	for s := range p.stages {
+		bound = append(bound, s)
+	}
+	p.mu.Unlock()
+
+	cause := context.Cause(p.ctx)
+	for _, s := range bound {
+		s.halt(cause)
+	}
+}
+
+// Pipeline returns the pipeline the main channel belongs to. If the channel
+// doesn't belong to a pipeline, a new one is created and the channel is bound
+// to it.
+func (c Chan[T]) Pipeline() *Pipeline {
+	s := lookup(c)
+	if s != nil {
+		if p := s.bound(); p != nil {
+			return p
+		}
+	}
+	p := NewPipeline()
+	p.attach(s)
+	return p
+}
+
+// In binds the main channel, and every stage feeding it, to the passed
+// pipeline. The main channel is returned so the call can be chained. Channels
+// not created by this package can be bound too, which makes stages reading from
+// them join the pipeline.
+func (c Chan[T]) In(p *Pipeline) Chan[T] {
+	p.attach(source(c))
+	return c
+}
+
+// source returns the stage feeding the passed channel. A stage without a
+// goroutine is registered for channels not created by this package.
+func source(c any) *stage {
+	s := newStage()
+	close(s.exited)
+	s.track(c)
+	for {
+		actual, loaded := stages.LoadOrStore(s.key, s)
+		if !loaded {
+			s.remember()
+			return s
+		}
+		if u := actual.(*stage); u.owns(key(c)) {
+			return u
+		}
+		stages.CompareAndDelete(s.key, actual)
+	}
+}
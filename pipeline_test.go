@@ -0,0 +1,261 @@
+package stream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := WithContext(ctx)
+
+	c := Repeat(1).In(p).Map(func(val int) int { return val * 2 })
+
+	assert.Equal(t, 2, <-c)
+	assert.Equal(t, 2, <-c)
+	assert.Len(t, p.stages, 2)
+
+	cancel()
+	c.Drain()
+	p.Wait()
+
+	p.mu.Lock()
+	assert.Empty(t, p.stages)
+	p.mu.Unlock()
+}
+
+func TestWithContextBlockedInput(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := WithContext(ctx)
+
+	c := FromChannel(make(chan int)).In(p).Take(5)
+
+	cancel()
+
+	assert.Equal(t, []int{}, c.Slice())
+}
+
+func TestWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	p := WithContext(ctx)
+	assert.Equal(t, []int{}, Iota(1, 10, 1).In(p).Slice())
+
+	p = WithContext(ctx)
+	result := Iota(1, 10, 1).In(p).Filter(func(val int) bool { return true }).Slice()
+
+	assert.Equal(t, []int{}, result)
+	assert.ErrorIs(t, p.Err(), context.Canceled)
+}
+
+func TestIn(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	p := WithContext(ctx)
+
+	c := RandInt().Take(2)
+	c.In(p)
+
+	assert.Len(t, p.stages, 2)
+	assert.Equal(t, 2, len(c.Slice()))
+}
+
+func ExampleWithContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	p := WithContext(ctx)
+
+	c := Cycle([]string{"Lorem", "ipsum"}).In(p)
+
+	fmt.Println(<-c, <-c, <-c)
+	cancel()
+	// Output: Lorem ipsum Lorem
+}
+
+// assertGoroutines asserts that the number of goroutines falls back to at most
+// the passed count. A stage is recorded as exited just before its goroutine
+// returns, so the count is polled. assert.Eventually can't be used as it
+// starts goroutines of its own.
+func assertGoroutines(t *testing.T, before int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	assert.LessOrEqual(t, runtime.NumGoroutine(), before)
+}
+
+func TestStop(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	p := NewPipeline()
+	a := Repeat(1).In(p)
+	b := Iota(0, 1000, 1).Map(func(val int) int { return val * 2 })
+	c := a.Chain(b).Filter(func(val int) bool { return val > 0 }).Stride(2)
+
+	assert.Equal(t, 1, <-c)
+	assert.Equal(t, 1, <-c)
+
+	p.Stop()
+
+	assertGoroutines(t, before)
+	assert.Equal(t, []int{}, c.Slice())
+}
+
+func TestStopUnwinds(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	p := NewPipeline()
+	result := Repeat("Lorem").In(p).Map(strings.ToUpper).Take(3).Slice()
+	p.Wait()
+
+	assert.Equal(t, []string{"LOREM", "LOREM", "LOREM"}, result)
+	assertGoroutines(t, before)
+	p.Stop()
+}
+
+func TestStopUnwindsLateBinding(t *testing.T) {
+	c := RandInt().Take(3)
+	p := c.Pipeline()
+
+	assert.Len(t, c.Slice(), 3)
+	p.Wait()
+	p.Stop()
+}
+
+func TestPipeline(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	c := Iota(1, 10, 1).In(p).Take(3)
+
+	assert.Same(t, p, c.Pipeline())
+	assert.NotSame(t, p, Iota(1, 10, 1).Pipeline())
+}
+
+func ExamplePipeline_Stop() {
+	p := NewPipeline()
+	c := Repeat("Lorem").In(p)
+
+	fmt.Println(<-c, <-c)
+	p.Stop()
+
+	fmt.Println(c.Slice())
+	// Output:
+	// Lorem Lorem
+	// []
+}
+
+func TestPipelineErr(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem"), iotest.ErrReader(failure))
+
+	p := NewPipeline()
+	c := FromReader(r).In(p).Take(10)
+
+	assert.Equal(t, []byte("Lorem"), c.Slice())
+	assert.Equal(t, failure, c.Err())
+	assert.Equal(t, failure, p.Err())
+	p.Stop()
+	assert.Equal(t, failure, p.Err())
+}
+
+func TestPipelineErrCancelled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	p := WithContext(ctx)
+	c := Repeat(1).In(p).Map(func(val int) int { return val + 1 })
+	c.Drain()
+
+	assert.ErrorIs(t, c.Err(), context.DeadlineExceeded)
+	assert.ErrorIs(t, p.Err(), context.DeadlineExceeded)
+}
+
+func TestPipelineErrUnwound(t *testing.T) {
+	p := NewPipeline()
+	c := Repeat(1).In(p).Take(3)
+
+	assert.Equal(t, []int{1, 1, 1}, c.Slice())
+	p.Wait()
+	assert.NoError(t, c.Err())
+	assert.NoError(t, p.Err())
+}
+
+func TestRegistryReleased(t *testing.T) {
+	registered := func() int {
+		n := 0
+		stages.Range(func(k, v any) bool {
+			n++
+			return true
+		})
+		return n
+	}
+	before := registered()
+
+	for range 1000 {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := WithContext(ctx)
+		Repeat("Lorem").In(p).Take(3).Drain()
+		cancel()
+		p.Wait()
+	}
+	for range 1000 {
+		c := FromReader(iotest.ErrReader(errors.New("failure")))
+		c.Drain()
+		assert.Error(t, c.Err())
+	}
+
+	assert.Eventually(t, func() bool {
+		runtime.GC()
+		return registered() < before+100
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPipelinePrunesStages(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	c := FlatMap(Repeat(1).In(p), func(val int) Chan[int] { return FromSlice([]int{val}) })
+	for range 1000 {
+		<-c
+	}
+
+	p.mu.Lock()
+	assert.Less(t, len(p.stages), 10)
+	p.mu.Unlock()
+}
+
+func TestCapacity(t *testing.T) {
+	p := NewPipeline(Capacity(8))
+	defer p.Stop()
+
+	a := Iota(0, 100, 1).In(p)
+	b := a.Map(func(val int) int { return val })
+	c := b.Filter(func(val int) bool { return true })
+
+	assert.Equal(t, 0, cap(a))
+	assert.Equal(t, 8, cap(b))
+	assert.Equal(t, 8, cap(c))
+	assert.Equal(t, 16, cap(c.Buffer(16)))
+	assert.Equal(t, 0, cap(Iota(0, 100, 1).Map(func(val int) int { return val })))
+}
+
+func ExampleCapacity() {
+	p := NewPipeline(Capacity(64))
+	defer p.Stop()
+
+	result := FromString("Lorem ipsum dolor", " ").In(p).Map(strings.ToUpper).Slice()
+
+	fmt.Println(result)
+	// Output: [LOREM IPSUM DOLOR]
+}
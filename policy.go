@@ -0,0 +1,143 @@
+package stream
+
+import "fmt"
+
+// Failure pairs a value with the error returned when it was processed.
+type Failure[T any] struct {
+	Val T
+	Err error
+}
+
+// ErrPolicy decides what a fallible operator does when its function returns
+// an error.
+type ErrPolicy interface {
+	policy()
+}
+
+type stopPolicy struct{}
+type skipPolicy struct{}
+type deadLetterPolicy[T any] struct {
+	failed chan<- Failure[T]
+}
+
+func (stopPolicy) policy()          {}
+func (skipPolicy) policy()          {}
+func (deadLetterPolicy[T]) policy() {}
+
+var (
+	// StopOnError closes the operator's channel on the first error. The error
+	// is reported by the channel's Err method.
+	StopOnError ErrPolicy = stopPolicy{}
+
+	// SkipOnError drops values that fail and carries on. A channel that
+	// skipped values isn't reported as truncated by its Err method, the values
+	// skipped are reported by its Skipped method instead.
+	SkipOnError ErrPolicy = skipPolicy{}
+)
+
+// DeadLetter sends values that fail, along with their errors, to the passed
+// channel and carries on. The channel is never closed by the operator and must
+// be read for the operator to make progress.
+func DeadLetter[T any](failed chan<- Failure[T]) ErrPolicy {
+	return deadLetterPolicy[T]{failed: failed}
+}
+
+// checkPolicy panics if the passed policy can't handle values of type T.
+func checkPolicy[T any](policy ErrPolicy) {
+	switch policy.(type) {
+	case stopPolicy, skipPolicy, deadLetterPolicy[T]:
+		return
+	}
+	panic(fmt.Sprintf("stream: %T can't handle values of type %T", policy, *new(T)))
+}
+
+// handle applies the policy to a value whose function failed. It returns false
+// if the stage should exit.
+func handle[T any](s *stage, policy ErrPolicy, val T, err error) bool {
+	switch p := policy.(type) {
+	case skipPolicy:
+		s.skip(err)
+		return true
+	case deadLetterPolicy[T]:
+		return send(s, p.failed, Failure[T]{Val: val, Err: err})
+	default:
+		s.fail(err)
+		return false
+	}
+}
+
+// SkipError reports the values dropped by SkipOnError.
+type SkipError struct {
+	// Count is the number of values dropped.
+	Count int
+
+	// Err is the error of the first value dropped.
+	Err error
+}
+
+func (e *SkipError) Error() string {
+	return fmt.Sprintf("stream: skipped %d values, the first because: %v", e.Count, e.Err)
+}
+
+func (e *SkipError) Unwrap() error {
+	return e.Err
+}
+
+// Skipped returns a *SkipError reporting the values dropped by SkipOnError in
+// the stages feeding the main channel, or nil if none were. It should be called
+// once the main channel has closed.
+func (c Chan[T]) Skipped() error {
+	s := lookup(c)
+	if s == nil {
+		return nil
+	}
+	var skipped SkipError
+	s.skipped(&skipped, make(map[*stage]bool))
+	return errSkipped(skipped)
+}
+
+// skip records a value dropped by SkipOnError because of the passed error.
+func (s *stage) skip(err error) {
+	s.mu.Lock()
+	s.skips.add(1, err)
+	p := s.pipeline
+	s.mu.Unlock()
+
+	if p != nil {
+		p.skip(1, err)
+	}
+}
+
+// skipped adds the values dropped by the stage, and the stages it reads from,
+// to the passed error. Stages already seen are skipped.
+func (s *stage) skipped(e *SkipError, seen map[*stage]bool) {
+	if seen[s] {
+		return
+	}
+	seen[s] = true
+	for _, u := range s.upstream {
+		u.skipped(e, seen)
+	}
+	s.mu.Lock()
+	e.add(s.skips.Count, s.skips.Err)
+	s.mu.Unlock()
+}
+
+// add records n dropped values, the first failing with the passed error.
+func (e *SkipError) add(n int, err error) {
+	if n == 0 {
+		return
+	}
+	if e.Count == 0 {
+		e.Err = err
+	}
+	e.Count += n
+}
+
+// errSkipped returns the passed record as an error, or nil if it is empty.
+func errSkipped(e SkipError) error {
+	if e.Count == 0 {
+		return nil
+	}
+	return &e
+}
@@ -25,48 +25,33 @@ func (c Chan[T]) Slice() []T {
 	return output
 }
 
-// WriteTo writes the main channel values as bytes to the writer argument.
+// WriteTo writes the main channel values as bytes to the writer argument, in
+// little endian byte order. Values of fixed size types can be read back using
+// ReadBinary. Use WriteToWith to choose another encoding. If the main channel
+// ended because of an error, that error is returned once the channel's values
+// have been written.
 func (c Chan[T]) WriteTo(w io.Writer) error {
-	for v := range c {
-		switch val := any(v).(type) {
-		case int:
-			err := binary.Write(w, binary.LittleEndian, int64(val))
-			if err != nil {
-				return err
-			}
-		case *int:
-			err := binary.Write(w, binary.LittleEndian, uint64(*val))
-			if err != nil {
-				return err
-			}
-		case uint:
-			err := binary.Write(w, binary.LittleEndian, uint64(val))
-			if err != nil {
-				return err
-			}
-		case *uint:
-			err := binary.Write(w, binary.LittleEndian, uint64(*val))
-			if err != nil {
-				return err
-			}
-		case string:
-			err := binary.Write(w, binary.LittleEndian, []byte(val))
-			if err != nil {
-				return err
-			}
-		case *string:
-			err := binary.Write(w, binary.LittleEndian, []byte(*val))
-			if err != nil {
-				return err
-			}
-		default:
-			err := binary.Write(w, binary.LittleEndian, val)
-			if err != nil {
-				return err
-			}
-		}
+	return c.WriteToWith(w, Raw[T]())
+}
+
+// write writes a single value as bytes to the writer argument. A *int is
+// written as an unsigned integer, as it always has been by WriteTo.
+func write(w io.Writer, v any) error {
+	if val, ok := v.(*int); ok {
+		return binary.Write(w, binary.LittleEndian, uint64(*val))
+	}
+	return writeBinary(w, binary.LittleEndian, v)
+}
+
+// Err returns the error that ended the main channel, if any. It should be
+// called once the main channel has closed to distinguish a stream that was
+// exhausted from one that was cut short by a failure or a cancelled pipeline.
+func (c Chan[T]) Err() error {
+	s := lookup(c)
+	if s == nil {
+		return nil
 	}
-	return nil
+	return s.error()
 }
 
 // String returns the string representation of the main channel values as a
@@ -85,11 +70,29 @@ func (c Chan[T]) String() string {
 
 }
 
-// Pop will return one value from the main channel.
+// Pop will return one value from the main channel. If the main channel is
+// closed, the zero value is returned. Use PopOk to tell a closed channel apart.
 func (c Chan[T]) Pop() T {
 	return <-c
 }
 
+// PopOk will return one value from the main channel and true, or the zero
+// value and false if the main channel is closed.
+func (c Chan[T]) PopOk() (T, bool) {
+	val, ok := <-c
+	return val, ok
+}
+
+// Count returns the number of main channel values once the main channel
+// closes.
+func (c Chan[T]) Count() int {
+	n := 0
+	for range c {
+		n++
+	}
+	return n
+}
+
 // Print will output the string representation of the main channel values to
 // stdout. This is useful for debugging.
 func (c Chan[T]) Print() {
@@ -2,8 +2,12 @@ package stream
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -174,6 +178,23 @@ func ExampleChan_Pop() {
 	// Output: 14
 }
 
+func TestPopOk(t *testing.T) {
+	c := FromSlice([]int{0})
+
+	val, ok := c.PopOk()
+	assert.True(t, ok)
+	assert.Equal(t, 0, val)
+
+	val, ok = c.PopOk()
+	assert.False(t, ok)
+	assert.Equal(t, 0, val)
+}
+
+func TestCount(t *testing.T) {
+	assert.Equal(t, 5, Iota(1, 6, 1).Count())
+	assert.Equal(t, 0, FromSlice([]int{}).Count())
+}
+
 func TestPrint(t *testing.T) {
 	Iota(2, 10, 2).Print()
 }
@@ -182,3 +203,21 @@ func ExampleChan_Print() {
 	Primes().Take(10).Print()
 	// Output: [2 3 5 7 11 13 17 19 23 29]
 }
+
+func TestWriteToError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem"), iotest.ErrReader(failure))
+
+	buf := new(bytes.Buffer)
+	err := FromReader(r).WriteTo(buf)
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, []byte("Lorem"), buf.Bytes())
+}
+
+func TestErr(t *testing.T) {
+	c := Iota(1, 5, 1)
+
+	assert.Equal(t, []int{1, 2, 3, 4}, c.Slice())
+	assert.NoError(t, c.Err())
+}
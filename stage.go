@@ -0,0 +1,321 @@
+package stream
+
+import (
+	"iter"
+	"reflect"
+	"runtime"
+	"sync"
+	"unsafe"
+	"weak"
+)
+
+// stages maps the address of each channel created by this package to the stage
+// feeding it. The map doesn't keep channels alive, an entry is removed once its
+// channel is unreachable, so a stage and its error are kept for exactly as long
+// as its channel can still be read.
+var stages sync.Map
+
+// stage tracks the goroutine that feeds a channel so it can be stopped and
+// bound to a pipeline after it has been created.
+type stage struct {
+	mu        sync.Mutex
+	key       uintptr
+	ref       weak.Pointer[byte]
+	pipeline  *Pipeline
+	upstream  []*stage
+	consumers int
+	orphaned  bool
+	unwound   bool
+	err       error
+	skips     SkipError
+	cause     error
+	stop      chan struct{}
+	halted    sync.Once
+	exited    chan struct{}
+}
+
+// key returns the identity of the passed channel.
+func key(c any) unsafe.Pointer {
+	return reflect.ValueOf(c).UnsafePointer()
+}
+
+// lookup returns the stage feeding the passed channel, if any.
+func lookup(c any) *stage {
+	k := key(c)
+	s, ok := stages.Load(uintptr(k))
+	if !ok || !s.(*stage).owns(k) {
+		return nil
+	}
+	return s.(*stage)
+}
+
+// owns returns true if the stage feeds the channel at the passed address. An
+// entry left behind by a collected channel whose address has been reused is not
+// the owner.
+func (s *stage) owns(k unsafe.Pointer) bool {
+	return unsafe.Pointer(s.ref.Value()) == k
+}
+
+// track identifies the stage by the passed channel, without keeping the
+// channel alive.
+func (s *stage) track(c any) {
+	k := key(c)
+	s.key = uintptr(k)
+	s.ref = weak.Make((*byte)(k))
+}
+
+// remember adds the stage to the registry until its channel is unreachable.
+func (s *stage) remember() {
+	if k := s.ref.Value(); k != nil {
+		runtime.AddCleanup(k, func(key uintptr) {
+			stages.CompareAndDelete(key, s)
+		}, s.key)
+	}
+}
+
+// newStage creates a stage reading from the passed channels.
+func newStage(inputs ...any) *stage {
+	s := &stage{
+		stop:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	for _, input := range inputs {
+		if u := lookup(input); u != nil {
+			s.follow(u)
+		}
+	}
+	return s
+}
+
+// follow records the passed stage as one the stage reads from.
+func (s *stage) follow(u *stage) {
+	u.retain()
+	s.upstream = append(s.upstream, u)
+}
+
+// retain records a new reader of the stage, to be matched by a call to release
+// once it has stopped reading.
+func (s *stage) retain() {
+	s.mu.Lock()
+	s.consumers++
+	s.orphaned = false
+	s.mu.Unlock()
+}
+
+// spawn creates a stage reading from the passed channels along with its output
+// channel. The output channel uses the default capacity of the pipeline the
+// stage will join.
+func spawn[C ~chan E, E any](inputs ...any) (*stage, C) {
+	s := newStage(inputs...)
+	return s, makeOutput[C](s, s.capacity())
+}
+
+// spawnN creates a stage reading from the passed channels along with an output
+// channel of the passed capacity.
+func spawnN[C ~chan E, E any](n int, inputs ...any) (*stage, C) {
+	s := newStage(inputs...)
+	return s, makeOutput[C](s, n)
+}
+
+// makeOutput creates and registers the output channel of the stage.
+func makeOutput[C ~chan E, E any](s *stage, n int) C {
+	c := make(C, n)
+	s.register(c)
+	return c
+}
+
+// capacity returns the default channel capacity of the pipeline the stage will
+// join.
+func (s *stage) capacity() int {
+	for _, u := range s.upstream {
+		if p := u.bound(); p != nil {
+			return p.capacity
+		}
+	}
+	return 0
+}
+
+// register records the passed channel as the output of the stage. The stage
+// then joins the pipeline of the first input that belongs to one.
+func (s *stage) register(output any) {
+	s.track(output)
+	stages.Store(s.key, s)
+	s.remember()
+	s.join()
+}
+
+// join binds the stage to the pipeline of the first input that belongs to one.
+func (s *stage) join() {
+	for _, u := range s.upstream {
+		if p := u.bound(); p != nil {
+			p.attach(s)
+			return
+		}
+	}
+}
+
+// bound returns the pipeline the stage belongs to, if any.
+func (s *stage) bound() *Pipeline {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.pipeline
+}
+
+// halt signals the stage to stop. The passed cause, if not nil, is recorded as
+// the stage's error if the stage exits because of it.
+func (s *stage) halt(cause error) {
+	s.halted.Do(func() {
+		s.cause = cause
+		close(s.stop)
+	})
+}
+
+// fail records the passed error as the reason the stage ended. Only the first
+// error is kept.
+func (s *stage) fail(err error) {
+	s.mu.Lock()
+	if s.err == nil {
+		s.err = err
+	}
+	p := s.pipeline
+	s.mu.Unlock()
+
+	if p != nil {
+		p.fail(err)
+	}
+}
+
+// error returns the error recorded for the stage.
+func (s *stage) error() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// settle records why the stage ended, which is either the first error of the
+// stages it was reading from or the reason it was stopped.
+func (s *stage) settle() {
+	for _, u := range s.upstream {
+		if err := u.error(); err != nil {
+			s.fail(err)
+			return
+		}
+	}
+	select {
+	case <-s.stop:
+		if s.cause != nil {
+			s.fail(s.cause)
+		}
+	default:
+	}
+}
+
+// interrupted returns true if the stage was stopped, or a stage it was reading
+// from ended with an error, so its inputs may not have been read in full.
+// Stages passing on a result once their inputs close check it first so a
+// partial result isn't mistaken for the real one.
+func (s *stage) interrupted() bool {
+	select {
+	case <-s.stop:
+		return true
+	default:
+	}
+	for _, u := range s.upstream {
+		if u.error() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// release is called when a stage reading from this one exits, or stops reading
+// from it. Once every reader has gone, the stage is unwound as nothing is left
+// to consume its values, if it belongs to a pipeline or the last reader was
+// itself unwound. Other stages may still be read directly, so are left alone.
+func (s *stage) release(unwind bool) {
+	s.mu.Lock()
+	s.consumers--
+	s.orphaned = s.consumers == 0
+	orphaned := s.orphaned
+	s.mu.Unlock()
+
+	if orphaned && (unwind || s.bound() != nil) {
+		s.unwind()
+	}
+}
+
+// unwind stops the stage because nothing is going to read from it again. The
+// stages it reads from are released as unwound when it exits, so those left
+// without readers are stopped in turn.
+func (s *stage) unwind() {
+	s.mu.Lock()
+	s.unwound = true
+	s.mu.Unlock()
+	s.halt(nil)
+}
+
+// exit records the stage's goroutine as finished, removes it from its
+// pipeline and releases the stages it was reading from.
+func (s *stage) exit() {
+	close(s.exited)
+	s.mu.Lock()
+	unwound := s.unwound
+	p := s.pipeline
+	s.mu.Unlock()
+	if p != nil {
+		p.remove(s)
+	}
+	for _, u := range s.upstream {
+		u.release(unwound)
+	}
+}
+
+// finish closes the output of a stage and records it as finished. It should be
+// deferred by every stage goroutine.
+func finish[T any](s *stage, output chan T) {
+	s.settle()
+	close(output)
+	s.exit()
+}
+
+// send sends a value to the passed channel. It returns false if the stage was
+// stopped before the value could be sent. A stopped stage never sends, even if
+// the channel is ready.
+func send[T any](s *stage, c chan<- T, val T) bool {
+	select {
+	case <-s.stop:
+		return false
+	default:
+	}
+	select {
+	case c <- val:
+		return true
+	case <-s.stop:
+		return false
+	}
+}
+
+// recv receives a value from the passed channel. It returns false if the
+// channel is closed or the stage was stopped.
+func recv[T any](s *stage, c <-chan T) (T, bool) {
+	select {
+	case val, ok := <-c:
+		return val, ok
+	case <-s.stop:
+		var zero T
+		return zero, false
+	}
+}
+
+// each iterates over the values of the passed channel until it is closed or
+// the stage is stopped.
+func each[T any](s *stage, c <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			val, ok := recv(s, c)
+			if !ok || !yield(val) {
+				return
+			}
+		}
+	}
+}
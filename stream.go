@@ -1,28 +1,50 @@
 package stream
 
+import "sync"
+
 // Generic channel types.
-type Chan[T comparable] chan T
-type ChanChan[T comparable] chan Chan[T]
-type ChanEnum[T comparable] chan Enum[T]
+type Chan[T any] chan T
+type ChanChan[T any] chan Chan[T]
+type ChanEnum[T any] chan Enum[T]
 
 // Enum adds an enumeration index to a value.
-type Enum[T comparable] struct {
+type Enum[T any] struct {
 	Index int `json:"index"`
 	Val   T   `json:"val"`
 }
 
 // Take returns n items from the main channel before closing it.
 func (c Chan[T]) Take(n int) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for i := 0; i < n; i++ {
-			val, ok := <-c
+			val, ok := recv(s, c)
 			if !ok {
 				return
 			}
-			output <- val
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Buffer returns the main channel values through a channel with a capacity of
+// n. This allows the stages feeding the main channel to run up to n values
+// ahead of the stages reading from it.
+func (c Chan[T]) Buffer(n int) Chan[T] {
+	s, output := spawnN[Chan[T]](n, c)
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -32,19 +54,17 @@ func (c Chan[T]) Take(n int) Chan[T] {
 // Until closes a channel when the passed function returns true, otherwise it
 // wll keep returning values. The passed function is called once for each value.
 func (c Chan[T]) Until(f func(val T) bool) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for {
-			val, ok := <-c
-			if !ok {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if f(val) {
 				return
 			}
-			if f(val) {
+			if !send(s, output, val) {
 				return
 			}
-			output <- val
 		}
 	}()
 
@@ -54,16 +74,40 @@ func (c Chan[T]) Until(f func(val T) bool) Chan[T] {
 // Map mutates main channel values based on the passed function. The passed
 // function is called once for each value.
 func (c Chan[T]) Map(f func(val T) T) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for {
-			val, ok := <-c
-			if !ok {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if !send(s, output, f(val)) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// MapErr mutates main channel values based on the passed function. If the
+// function returns an error, the passed policy decides what happens to the
+// value. The passed function is called once for each value.
+func (c Chan[T]) MapErr(f func(val T) (T, error), policy ErrPolicy) Chan[T] {
+	checkPolicy[T](policy)
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			res, err := f(val)
+			if err != nil {
+				if !handle(s, policy, val, err) {
+					return
+				}
+				continue
+			}
+			if !send(s, output, res) {
 				return
 			}
-			output <- f(val)
 		}
 	}()
 
@@ -73,17 +117,39 @@ func (c Chan[T]) Map(f func(val T) T) Chan[T] {
 // Filter filters main channel values based on the passed function returning
 // true. The passed function is called once for each value.
 func (c Chan[T]) Filter(f func(val T) bool) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for {
-			val, ok := <-c
-			if !ok {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if f(val) && !send(s, output, val) {
 				return
 			}
-			if f(val) {
-				output <- val
+		}
+	}()
+
+	return output
+}
+
+// FilterErr filters main channel values based on the passed function
+// returning true. If the function returns an error, the passed policy decides
+// what happens to the value. The passed function is called once for each value.
+func (c Chan[T]) FilterErr(f func(val T) (bool, error), policy ErrPolicy) Chan[T] {
+	checkPolicy[T](policy)
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			keep, err := f(val)
+			if err != nil {
+				if !handle(s, policy, val, err) {
+					return
+				}
+				continue
+			}
+			if keep && !send(s, output, val) {
+				return
 			}
 		}
 	}()
@@ -92,34 +158,108 @@ func (c Chan[T]) Filter(f func(val T) bool) Chan[T] {
 }
 
 // Reduce reduces main channel values to one value based on the passed function.
-// The passed function is called once for each value.
+// If the main channel is empty, the zero value is returned. Use ReduceOpt to
+// tell an empty channel apart. Nothing is returned if the main channel ended
+// because of an error or the stage was stopped. The passed function is called
+// once for each value.
 func (c Chan[T]) Reduce(f func(a, b T) T) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
+		a, _ := recv(s, c)
+		for val := range each(s, c) {
+			a = f(a, val)
+		}
+		if s.interrupted() {
+			return
+		}
+		send(s, output, a)
+	}()
+
+	return output
+}
 
-		var a T = <-c
-		for val := range c {
+// ReduceOpt reduces main channel values to one value based on the passed
+// function. If the main channel is empty, ended because of an error or the
+// stage was stopped, the returned channel closes without a value. The passed
+// function is called once for each value.
+func (c Chan[T]) ReduceOpt(f func(a, b T) T) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		a, ok := recv(s, c)
+		if !ok {
+			return
+		}
+		for val := range each(s, c) {
 			a = f(a, val)
 		}
-		output <- a
+		if s.interrupted() {
+			return
+		}
+		send(s, output, a)
 	}()
 
 	return output
 }
 
-// Last will return the final value from the channel once it is closed.
+// Scan passes on the running result of reducing main channel values based on
+// the passed function, starting with the passed initial value. Unlike Reduce, a
+// value is passed on for every main channel value so it can be used on channels
+// that never close. The passed function is called once for each value.
+func (c Chan[T]) Scan(init T, f func(acc, val T) T) Chan[T] {
+	return ScanTo(c, init, f)
+}
+
+// First will return the first value from the channel. If the main channel is
+// empty, the returned channel closes without a value.
+func (c Chan[T]) First() Chan[T] {
+	return c.Take(1)
+}
+
+// Last will return the final value from the channel once it is closed. If the
+// main channel is empty, the zero value is returned. Use LastOpt to tell an
+// empty channel apart. Nothing is returned if the main channel ended because of
+// an error or the stage was stopped.
 func (c Chan[T]) Last() Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		var last T = <-c
-		for val := range c {
+		defer finish(s, output)
+		last, _ := recv(s, c)
+		for val := range each(s, c) {
 			last = val
 		}
-		output <- last
+		if s.interrupted() {
+			return
+		}
+		send(s, output, last)
+	}()
+
+	return output
+}
+
+// LastOpt will return the final value from the channel once it is closed. If
+// the main channel is empty, ended because of an error or the stage was
+// stopped, the returned channel closes without a value.
+func (c Chan[T]) LastOpt() Chan[T] {
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		last, ok := recv(s, c)
+		if !ok {
+			return
+		}
+		for val := range each(s, c) {
+			last = val
+		}
+		if s.interrupted() {
+			return
+		}
+		send(s, output, last)
 	}()
 
 	return output
@@ -128,19 +268,15 @@ func (c Chan[T]) Last() Chan[T] {
 // Chain will append values from the passed channels to the end of the main
 // channel.
 func (c Chan[T]) Chain(b Chan[T], args ...Chan[T]) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](inputs(c, b, args)...)
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			output <- val
-		}
-		for val := range b {
-			output <- val
-		}
-		for _, arg := range args {
-			for val := range arg {
-				output <- val
+		defer finish(s, output)
+		for _, input := range append([]Chan[T]{c, b}, args...) {
+			for val := range each(s, input) {
+				if !send(s, output, val) {
+					return
+				}
 			}
 		}
 	}()
@@ -151,27 +287,33 @@ func (c Chan[T]) Chain(b Chan[T], args ...Chan[T]) Chan[T] {
 // RoundRobin will return alternate values from the main channel and the passed
 // channels, in order.
 func (c Chan[T]) RoundRobin(b Chan[T], args ...Chan[T]) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](inputs(c, b, args)...)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
 			available := false
-			val, ok := <-c
+			val, ok := recv(s, c)
 			if ok {
 				available = true
-				output <- val
+				if !send(s, output, val) {
+					return
+				}
 			}
-			val, ok = <-b
+			val, ok = recv(s, b)
 			if ok {
 				available = true
-				output <- val
+				if !send(s, output, val) {
+					return
+				}
 			}
 			for _, arg := range args {
-				val, ok = <-arg
+				val, ok = recv(s, arg)
 				if ok {
 					available = true
-					output <- val
+					if !send(s, output, val) {
+						return
+					}
 				}
 				break
 			}
@@ -184,28 +326,57 @@ func (c Chan[T]) RoundRobin(b Chan[T], args ...Chan[T]) Chan[T] {
 	return output
 }
 
+// Merge returns values from the main channel and the passed channels as soon as
+// any of them has one ready. The returned channel closes once they have all
+// closed.
+func (c Chan[T]) Merge(b Chan[T], args ...Chan[T]) Chan[T] {
+	s, output := spawn[Chan[T]](inputs(c, b, args)...)
+
+	var wg sync.WaitGroup
+	for _, input := range append([]Chan[T]{c, b}, args...) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for val := range each(s, input) {
+				if !send(s, output, val) {
+					return
+				}
+			}
+		}()
+	}
+
+	go func() {
+		defer finish(s, output)
+		wg.Wait()
+	}()
+
+	return output
+}
+
 // Chunk returns a channel full of channels of the passed length, filled with
 // values of the main channel.
 func (c Chan[T]) Chunk(n int) ChanChan[T] {
-	output := make(ChanChan[T])
+	s, output := spawn[ChanChan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
 			chunk := make(Chan[T], n)
 			for i := 0; i < n; i++ {
-				val, ok := <-c
+				val, ok := recv(s, c)
 				if !ok {
 					if i > 0 {
 						close(chunk)
-						output <- chunk
+						send(s, output, chunk)
 					}
 					return
 				}
 				chunk <- val
 			}
 			close(chunk)
-			output <- chunk
+			if !send(s, output, chunk) {
+				return
+			}
 		}
 	}()
 
@@ -214,18 +385,20 @@ func (c Chan[T]) Chunk(n int) ChanChan[T] {
 
 // Drop removes n values from the main channel before continuing.
 func (c Chan[T]) Drop(n int) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for i := 0; i < n; i++ {
-			_, ok := <-c
+			_, ok := recv(s, c)
 			if !ok {
 				return
 			}
 		}
-		for val := range c {
-			output <- val
+		for val := range each(s, c) {
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -235,14 +408,16 @@ func (c Chan[T]) Drop(n int) Chan[T] {
 // Stride iterates over channel values returning every n value of the main
 // channel.
 func (c Chan[T]) Stride(n int) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		i := 0
-		for val := range c {
+		for val := range each(s, c) {
 			if i%n == 0 {
-				output <- val
+				if !send(s, output, val) {
+					return
+				}
 				i = 0
 			}
 			i++
@@ -253,15 +428,16 @@ func (c Chan[T]) Stride(n int) Chan[T] {
 }
 
 // Tail returns a channel containing the last n values of the main channel once
-// it's closed.
+// it's closed. Nothing is returned if the main channel ended because of an
+// error or the stage was stopped.
 func (c Chan[T]) Tail(n int) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		tail := make(Chan[T], n)
 		i := 0
-		for val := range c {
+		for val := range each(s, c) {
 			if i >= n {
 				<-tail
 			} else {
@@ -269,9 +445,14 @@ func (c Chan[T]) Tail(n int) Chan[T] {
 			}
 			tail <- val
 		}
+		if s.interrupted() {
+			return
+		}
 		close(tail)
 		for val := range tail {
-			output <- val
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -281,24 +462,24 @@ func (c Chan[T]) Tail(n int) Chan[T] {
 // Zip returns a channel of channels containing the next values of the main
 // channel and all other passed channels, in order.
 func (c Chan[T]) Zip(b Chan[T], args ...Chan[T]) ChanChan[T] {
-	output := make(ChanChan[T])
+	s, output := spawn[ChanChan[T]](inputs(c, b, args)...)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		for {
 			zip := make(Chan[T], len(args)+2)
-			val, ok := <-c
+			val, ok := recv(s, c)
 			if !ok {
 				return
 			}
 			zip <- val
-			val, ok = <-b
+			val, ok = recv(s, b)
 			if !ok {
 				return
 			}
 			zip <- val
 			for _, arg := range args {
-				val, ok = <-arg
+				val, ok = recv(s, arg)
 				if !ok {
 					return
 				}
@@ -306,7 +487,9 @@ func (c Chan[T]) Zip(b Chan[T], args ...Chan[T]) ChanChan[T] {
 				break
 			}
 			close(zip)
-			output <- zip
+			if !send(s, output, zip) {
+				return
+			}
 		}
 	}()
 
@@ -316,18 +499,20 @@ func (c Chan[T]) Zip(b Chan[T], args ...Chan[T]) ChanChan[T] {
 // PadRight adds values to the end of the main channel if that channel's values
 // are fewer than the passed padding amount once the channel is closed.
 func (c Chan[T]) PadRight(val T, n int) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
+		defer finish(s, output)
 		i := 0
-		for val := range c {
-			output <- val
+		for val := range each(s, c) {
+			if !send(s, output, val) {
+				return
+			}
 			i++
 		}
-		if i < n {
-			for val := range Repeat(val).Take(n - i) {
-				output <- val
+		for ; i < n; i++ {
+			if !send(s, output, val) {
+				return
 			}
 		}
 	}()
@@ -338,14 +523,16 @@ func (c Chan[T]) PadRight(val T, n int) Chan[T] {
 // PadLeft adds values to the beginning of the main channel if that channel's values
 // are fewer than the passed padding amount.
 func (c Chan[T]) PadLeft(val T, n int) Chan[T] {
+	s := newStage(c)
 	output := make(Chan[T], n)
+	s.register(output)
 
-	for val := range Repeat(val).Take(n) {
+	for i := 0; i < n; i++ {
 		output <- val
 	}
 
 	for i := 0; i < n; i++ {
-		val, ok := <-c
+		val, ok := recv(s, c)
 		if !ok {
 			break
 		}
@@ -354,9 +541,11 @@ func (c Chan[T]) PadLeft(val T, n int) Chan[T] {
 	}
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			output <- val
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -366,13 +555,41 @@ func (c Chan[T]) PadLeft(val T, n int) Chan[T] {
 // Tee passes each main channel value to the passed function. The passed
 // function is called once for each value.
 func (c Chan[T]) Tee(f func(val T)) Chan[T] {
-	output := make(Chan[T])
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for val := range c {
+		defer finish(s, output)
+		for val := range each(s, c) {
 			f(val)
-			output <- val
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// TeeErr passes each main channel value to the passed function. If the
+// function returns an error, the passed policy decides what happens to the
+// value, otherwise it is passed on. The passed function is called once for
+// each value.
+func (c Chan[T]) TeeErr(f func(val T) error, policy ErrPolicy) Chan[T] {
+	checkPolicy[T](policy)
+	s, output := spawn[Chan[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if err := f(val); err != nil {
+				if !handle(s, policy, val, err) {
+					return
+				}
+				continue
+			}
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
@@ -382,15 +599,18 @@ func (c Chan[T]) Tee(f func(val T)) Chan[T] {
 // Enumerate decorates main channel values with an enumerated index starting at
 // the passed n.
 func (c Chan[T]) Enumerate(n int) ChanEnum[T] {
-	output := make(ChanEnum[T])
+	s, output := spawn[ChanEnum[T]](c)
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			output <- Enum[T]{
+		defer finish(s, output)
+		for val := range each(s, c) {
+			enum := Enum[T]{
 				Index: n,
 				Val:   val,
 			}
+			if !send(s, output, enum) {
+				return
+			}
 			n++
 		}
 	}()
@@ -398,60 +618,100 @@ func (c Chan[T]) Enumerate(n int) ChanEnum[T] {
 	return output
 }
 
-// Find drains the main channel until the passed needle value is found then
-// normal iteration continues.
-func (c Chan[T]) Find(needle T) Chan[T] {
-	output := make(Chan[T])
+// Find drains the passed channel until the passed needle value is found then
+// normal iteration continues. Use FindBy for values that are not comparable.
+func Find[T comparable](c Chan[T], needle T) Chan[T] {
+	return c.FindBy(needle, equal)
+}
+
+// FindBy drains the main channel until a value equal to the passed needle is
+// found then normal iteration continues. Values are compared using the passed
+// function.
+func (c Chan[T]) FindBy(needle T, eq func(a, b T) bool) Chan[T] {
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			if val == needle {
-				output <- val
-				break
+		defer finish(s, output)
+		found := false
+		for val := range each(s, c) {
+			if !found && !eq(val, needle) {
+				continue
+			}
+			found = true
+			if !send(s, output, val) {
+				return
 			}
-		}
-		for val := range c {
-			output <- val
 		}
 	}()
 
 	return output
 }
 
-// Substitute iterates over main channel values replacing the passed old value
-// with the new value.
-func (c Chan[T]) Substitute(old, new T) Chan[T] {
-	output := make(Chan[T])
+// Substitute iterates over the passed channel's values replacing the passed old
+// value with the new value. Use SubstituteBy for values that are not
+// comparable.
+func Substitute[T comparable](c Chan[T], old, new T) Chan[T] {
+	return c.SubstituteBy(old, new, equal)
+}
+
+// SubstituteBy iterates over main channel values replacing those equal to the
+// passed old value with the new value. Values are compared using the passed
+// function.
+func (c Chan[T]) SubstituteBy(old, new T, eq func(a, b T) bool) Chan[T] {
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			if val == old {
-				output <- new
-				continue
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if eq(val, old) {
+				val = new
+			}
+			if !send(s, output, val) {
+				return
 			}
-			output <- val
 		}
 	}()
 
 	return output
 }
 
-// Skip iterates over main channel values skipping those equal to the passed
-// value.
-func (c Chan[T]) Skip(needle T) Chan[T] {
-	output := make(Chan[T])
+// Skip iterates over the passed channel's values skipping those equal to the
+// passed value. Use SkipBy for values that are not comparable.
+func Skip[T comparable](c Chan[T], needle T) Chan[T] {
+	return c.SkipBy(needle, equal)
+}
+
+// SkipBy iterates over main channel values skipping those equal to the passed
+// value. Values are compared using the passed function.
+func (c Chan[T]) SkipBy(needle T, eq func(a, b T) bool) Chan[T] {
+	s, output := spawn[Chan[T]](c)
 
 	go func() {
-		defer close(output)
-		for val := range c {
-			if val == needle {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if eq(val, needle) {
 				continue
 			}
-			output <- val
+			if !send(s, output, val) {
+				return
+			}
 		}
 	}()
 
 	return output
 }
+
+// equal compares two values using ==.
+func equal[T comparable](a, b T) bool {
+	return a == b
+}
+
+// inputs collects the main channel and the passed channels of a variadic
+// operator.
+func inputs[T any](c, b Chan[T], args []Chan[T]) []any {
+	all := []any{c, b}
+	for _, arg := range args {
+		all = append(all, arg)
+	}
+	return all
+}
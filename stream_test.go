@@ -1,8 +1,19 @@
 package stream
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"testing/iotest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,6 +46,23 @@ func TestTakeNotEnough(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestBuffer(t *testing.T) {
+	var read atomic.Int32
+	c := Iota(0, 10, 1).Tee(func(val int) { read.Add(1) }).Buffer(5)
+
+	assert.Eventually(t, func() bool { return read.Load() >= 5 }, time.Second, time.Millisecond)
+	assert.Equal(t, 5, cap(c))
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, c.Slice())
+}
+
+func ExampleChan_Buffer() {
+	r := strings.NewReader("Lorem ipsum")
+	result := FromReader(r).Buffer(4096).Take(5).Slice()
+
+	fmt.Println(string(result))
+	// Output: Lorem
+}
+
 func TestUntil(t *testing.T) {
 	expected := []int{1, 2, 3, 4, 5}
 	result := Iota(1, 10, 1).Until(func(val int) bool { return val > 5 }).Slice()
@@ -90,6 +118,76 @@ func ExampleChan_Map() {
 	// Output: Yberz vcfhz qbybe fvg nzrg
 }
 
+func TestMapErrStop(t *testing.T) {
+	c := FromString("1 2 x 4", " ").MapErr(func(val string) (string, error) {
+		_, err := strconv.Atoi(val)
+		return val + val, err
+	}, StopOnError)
+
+	assert.Equal(t, []string{"11", "22"}, c.Slice())
+	assert.ErrorIs(t, c.Err(), strconv.ErrSyntax)
+}
+
+func TestMapErrSkip(t *testing.T) {
+	c := FromString("1 2 x 4", " ").MapErr(func(val string) (string, error) {
+		_, err := strconv.Atoi(val)
+		return val + val, err
+	}, SkipOnError).Map(strings.ToUpper)
+
+	assert.Equal(t, []string{"11", "22", "44"}, c.Slice())
+	assert.NoError(t, c.Err())
+	assert.ErrorIs(t, c.Skipped(), strconv.ErrSyntax)
+
+	var skipped *SkipError
+	assert.ErrorAs(t, c.Skipped(), &skipped)
+	assert.Equal(t, 1, skipped.Count)
+
+	p := c.Pipeline()
+	assert.NoError(t, p.Err())
+	assert.Equal(t, c.Skipped(), p.Skipped())
+}
+
+func TestMapErrDeadLetter(t *testing.T) {
+	failed := make(chan Failure[string], 2)
+	c := FromString("1 x 3 y", " ").MapErr(func(val string) (string, error) {
+		_, err := strconv.Atoi(val)
+		return val + val, err
+	}, DeadLetter(failed))
+
+	assert.Equal(t, []string{"11", "33"}, c.Slice())
+	assert.NoError(t, c.Err())
+
+	f := <-failed
+	assert.Equal(t, "x", f.Val)
+	assert.ErrorIs(t, f.Err, strconv.ErrSyntax)
+	assert.Equal(t, "y", (<-failed).Val)
+}
+
+func TestMapErrDeadLetterType(t *testing.T) {
+	failed := make(chan Failure[int])
+
+	assert.Panics(t, func() {
+		FromString("1 2", " ").MapErr(func(val string) (string, error) {
+			return val, nil
+		}, DeadLetter(failed))
+	})
+}
+
+func ExampleChan_MapErr() {
+	failed := make(chan Failure[string], 1)
+
+	result := FromString("1 2 x 4", " ").MapErr(func(val string) (string, error) {
+		n, err := strconv.Atoi(val)
+		return strconv.Itoa(n * 2), err
+	}, DeadLetter(failed)).Slice()
+
+	fmt.Println(result)
+	fmt.Println((<-failed).Val)
+	// Output:
+	// [2 4 8]
+	// x
+}
+
 func TestFilter(t *testing.T) {
 	expected := []int{2, 4, 6, 8, 10}
 	result := Iota(1, 12, 1).Filter(func(val int) bool { return val%2 == 0 }).Slice()
@@ -108,6 +206,38 @@ func ExampleChan_Filter() {
 	// Output: [2 4 6 8 10]
 }
 
+func TestFilterErr(t *testing.T) {
+	even := func(val string) (bool, error) {
+		n, err := strconv.Atoi(val)
+		return n%2 == 0, err
+	}
+
+	c := FromString("1 2 x 4", " ").FilterErr(even, StopOnError)
+	assert.Equal(t, []string{"2"}, c.Slice())
+	assert.ErrorIs(t, c.Err(), strconv.ErrSyntax)
+
+	c = FromString("1 x 2 y 4", " ").FilterErr(even, SkipOnError)
+	assert.Equal(t, []string{"2", "4"}, c.Slice())
+	assert.NoError(t, c.Err())
+	assert.ErrorIs(t, c.Skipped(), strconv.ErrSyntax)
+	assert.EqualError(t, c.Skipped(), `stream: skipped 2 values, the first because: strconv.Atoi: parsing "x": invalid syntax`)
+}
+
+func ExampleChan_FilterErr() {
+	even := func(val string) (bool, error) {
+		n, err := strconv.Atoi(val)
+		return n%2 == 0, err
+	}
+
+	c := FromString("1 2 x 4", " ").FilterErr(even, SkipOnError)
+
+	fmt.Println(c.Slice())
+	fmt.Println(c.Skipped())
+	// Output:
+	// [2 4]
+	// stream: skipped 1 values, the first because: strconv.Atoi: parsing "x": invalid syntax
+}
+
 func TestReduce(t *testing.T) {
 	expected := 45
 	result := Iota(1, 10, 1).Reduce(func(a, b int) int { return a + b }).Pop()
@@ -115,6 +245,39 @@ func TestReduce(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestResultInterrupted(t *testing.T) {
+	sum := func(a, b byte) byte { return a + b }
+	results := map[string]func(c Chan[byte]) Chan[byte]{
+		"Reduce":    func(c Chan[byte]) Chan[byte] { return c.Reduce(sum) },
+		"Last":      Chan[byte].Last,
+		"Tail":      func(c Chan[byte]) Chan[byte] { return c.Tail(2) },
+		"ReduceOpt": func(c Chan[byte]) Chan[byte] { return c.ReduceOpt(sum) },
+		"LastOpt":   Chan[byte].LastOpt,
+		"ReduceTo":  func(c Chan[byte]) Chan[byte] { return ReduceTo(c, 0, sum) },
+	}
+
+	for name, result := range results {
+		t.Run(name, func(t *testing.T) {
+			failure := errors.New("failure")
+			r := io.MultiReader(strings.NewReader("Lorem"), iotest.ErrReader(failure))
+			c := result(FromReader(r))
+			assert.Equal(t, []byte{}, c.Slice())
+			assert.ErrorIs(t, c.Err(), failure)
+
+			for range 100 {
+				ctx, cancel := context.WithCancel(context.Background())
+				in := make(chan byte)
+				c := result(FromChannel(in).In(WithContext(ctx)))
+				in <- 1
+				in <- 2
+				cancel()
+				assert.Equal(t, []byte{}, c.Slice())
+				assert.ErrorIs(t, c.Err(), context.Canceled)
+			}
+		})
+	}
+}
+
 func ExampleChan_Reduce() {
 	sum := func(a, b int) int {
 		return a + b
@@ -126,6 +289,65 @@ func ExampleChan_Reduce() {
 	// Output: 45
 }
 
+func TestReduceEmpty(t *testing.T) {
+	sum := func(a, b int) int { return a + b }
+
+	assert.Equal(t, []int{0}, FromSlice([]int{}).Reduce(sum).Slice())
+	assert.Equal(t, []int{}, FromSlice([]int{}).ReduceOpt(sum).Slice())
+}
+
+func TestReduceOpt(t *testing.T) {
+	expected := 45
+	result, ok := Iota(1, 10, 1).ReduceOpt(func(a, b int) int { return a + b }).PopOk()
+
+	assert.True(t, ok)
+	assert.Equal(t, expected, result)
+}
+
+func ExampleChan_ReduceOpt() {
+	sum := func(a, b int) int {
+		return a + b
+	}
+
+	_, ok := Iota(1, 10, 1).Filter(func(val int) bool {
+		return val > 10
+	}).ReduceOpt(sum).PopOk()
+
+	fmt.Println(ok)
+	// Output: false
+}
+
+func TestScan(t *testing.T) {
+	expected := []int{1, 3, 6, 10, 15}
+	result := Iota(1, 6, 1).Scan(0, func(acc, val int) int { return acc + val }).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestScanUnbounded(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	expected := []int{2, 4, 8, 16}
+	result := Repeat(2).In(p).Scan(1, func(acc, val int) int { return acc * val }).Take(4).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleChan_Scan() {
+	highest := func(acc, val int) int {
+		if val > acc {
+			return val
+		}
+		return acc
+	}
+
+	result := FromSlice([]int{3, 1, 4, 1, 5}).Scan(0, highest).Slice()
+
+	fmt.Println(result)
+	// Output: [3 3 4 4 5]
+}
+
 func TestLast(t *testing.T) {
 	expected := 9
 	result := Iota(1, 10, 1).Last().Pop()
@@ -133,6 +355,27 @@ func TestLast(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestLastOpt(t *testing.T) {
+	result, ok := Iota(1, 10, 1).LastOpt().PopOk()
+	assert.True(t, ok)
+	assert.Equal(t, 9, result)
+
+	_, ok = FromSlice([]int{}).LastOpt().PopOk()
+	assert.False(t, ok)
+}
+
+func TestFirst(t *testing.T) {
+	assert.Equal(t, []int{1}, Iota(1, 10, 1).First().Slice())
+	assert.Equal(t, []int{}, FromSlice([]int{}).First().Slice())
+}
+
+func ExampleChan_First() {
+	result := FromString("Lorem ipsum dolor", " ").First().Pop()
+
+	fmt.Println(result)
+	// Output: Lorem
+}
+
 func ExampleChan_Last() {
 	result := Iota(1, 10, 1).Last().Pop()
 
@@ -173,6 +416,34 @@ func TestChain(t *testing.T) {
 	assert.Equal(t, expected, result)
 }
 
+func TestMerge(t *testing.T) {
+	expected := Iota(0, 15, 1).Slice()
+	result := Iota(0, 5, 1).Merge(Iota(5, 10, 1), Iota(10, 15, 1)).Slice()
+
+	assert.ElementsMatch(t, expected, result)
+}
+
+func TestMergeSlowSource(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	slow := Chan[int](make(chan int)).In(p)
+	result := slow.Merge(Repeat(1).In(p)).Take(3).Slice()
+
+	assert.Equal(t, []int{1, 1, 1}, result)
+}
+
+func ExampleChan_Merge() {
+	a := FromSlice([]int{1, 2, 3})
+	b := FromSlice([]int{4, 5, 6})
+
+	result := a.Merge(b).Slice()
+	slices.Sort(result)
+
+	fmt.Println(result)
+	// Output: [1 2 3 4 5 6]
+}
+
 func TestRoundRobinVariadic1(t *testing.T) {
 	expected := "0aA1bB2cC3dD4eE5fF6gG7hH8I9JKLMNOPQRSTUVWXYZ"
 
@@ -418,6 +689,48 @@ func ExampleChan_Tee() {
 	// Output: values: [1 2 3 4 5] count: 5
 }
 
+func TestTeeErr(t *testing.T) {
+	failure := errors.New("failure")
+	store := func(val int) error {
+		if val == 3 {
+			return failure
+		}
+		return nil
+	}
+
+	c := Iota(1, 6, 1).TeeErr(store, StopOnError)
+	assert.Equal(t, []int{1, 2}, c.Slice())
+	assert.Equal(t, failure, c.Err())
+
+	c = Iota(1, 6, 1).TeeErr(store, SkipOnError)
+	assert.Equal(t, []int{1, 2, 4, 5}, c.Slice())
+	assert.NoError(t, c.Err())
+	assert.ErrorIs(t, c.Skipped(), failure)
+
+	failed := make(chan Failure[int], 1)
+	c = Iota(1, 6, 1).TeeErr(store, DeadLetter(failed))
+	assert.Equal(t, []int{1, 2, 4, 5}, c.Slice())
+	assert.NoError(t, c.Err())
+	assert.Equal(t, Failure[int]{Val: 3, Err: failure}, <-failed)
+}
+
+func ExampleChan_TeeErr() {
+	store := func(val int) error {
+		if val == 3 {
+			return errors.New("failure")
+		}
+		return nil
+	}
+
+	c := Iota(1, 6, 1).TeeErr(store, StopOnError)
+
+	fmt.Println(c.Slice())
+	fmt.Println(c.Err())
+	// Output:
+	// [1 2]
+	// failure
+}
+
 func TestEnumerate(t *testing.T) {
 	expected := []Enum[string]{
 		{
@@ -453,27 +766,59 @@ func ExampleChan_Enumerate() {
 
 func TestFind(t *testing.T) {
 	expected := []string{"dolor", "sit", "amet"}
-	result := FromString("Lorem ipsum dolor sit amet", " ").Find("dolor").Slice()
+	result := Find(FromString("Lorem ipsum dolor sit amet", " "), "dolor").Slice()
 
 	assert.Equal(t, expected, result)
 }
 
-func ExampleChan_Find() {
-	result := FromString("Lorem ipsum dolor sit amet", " ").Find("dolor").Slice()
+func ExampleFind() {
+	result := Find(FromString("Lorem ipsum dolor sit amet", " "), "dolor").Slice()
 
 	fmt.Println(result)
 	// Output: [dolor sit amet]
 }
 
+func TestFindBy(t *testing.T) {
+	expected := [][]byte{[]byte("dolor"), []byte("sit")}
+	result := FromSlice(bytes.Fields([]byte("Lorem ipsum dolor sit"))).FindBy([]byte("dolor"), bytes.Equal).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleChan_FindBy() {
+	records := FromSlice([][]byte{[]byte("Lorem"), []byte("ipsum"), []byte("dolor")})
+
+	for val := range records.FindBy([]byte("ipsum"), bytes.Equal) {
+		fmt.Println(string(val))
+	}
+	// Output:
+	// ipsum
+	// dolor
+}
+
 func TestSubstitute(t *testing.T) {
 	expected := []string{"Lorem", "ipsum", "lectus", "sit", "amet"}
-	result := FromString("Lorem ipsum dolor sit amet", " ").Substitute("dolor", "lectus").Slice()
+	result := Substitute(FromString("Lorem ipsum dolor sit amet", " "), "dolor", "lectus").Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleSubstitute() {
+	result := Substitute(FromString("Lorem ipsum dolor sit amet", " "), "dolor", "lectus").Slice()
+
+	fmt.Println(result)
+	// Output: [Lorem ipsum lectus sit amet]
+}
+
+func TestSubstituteBy(t *testing.T) {
+	expected := []string{"Lorem", "ipsum", "lectus", "sit", "amet"}
+	result := FromString("Lorem ipsum DOLOR sit amet", " ").SubstituteBy("dolor", "lectus", strings.EqualFold).Slice()
 
 	assert.Equal(t, expected, result)
 }
 
-func ExampleChan_Substitute() {
-	result := FromString("Lorem ipsum dolor sit amet", " ").Substitute("dolor", "lectus").Slice()
+func ExampleChan_SubstituteBy() {
+	result := FromString("Lorem ipsum DOLOR sit amet", " ").SubstituteBy("dolor", "lectus", strings.EqualFold).Slice()
 
 	fmt.Println(result)
 	// Output: [Lorem ipsum lectus sit amet]
@@ -481,14 +826,31 @@ func ExampleChan_Substitute() {
 
 func TestSkip(t *testing.T) {
 	expected := []int{1, 3, 1, 3, 1, 3}
-	result := FromSlice([]int{1, 2, 3, 1, 2, 3, 1, 2, 3}).Skip(2).Slice()
+	result := Skip(FromSlice([]int{1, 2, 3, 1, 2, 3, 1, 2, 3}), 2).Slice()
 
 	assert.Equal(t, expected, result)
 }
 
-func ExampleChan_Skip() {
-	result := FromSlice([]int{1, 2, 3, 1, 2, 3, 1, 2, 3}).Skip(2).Slice()
+func ExampleSkip() {
+	result := Skip(FromSlice([]int{1, 2, 3, 1, 2, 3, 1, 2, 3}), 2).Slice()
 
 	fmt.Println(result)
 	// Output: [1 3 1 3 1 3]
 }
+
+func TestSkipBy(t *testing.T) {
+	expected := []map[string]int{{"a": 1}, {"c": 3}}
+	input := []map[string]int{{"a": 1}, {"b": 2}, {"c": 3}}
+	result := FromSlice(input).SkipBy(map[string]int{"b": 2}, maps.Equal).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleChan_SkipBy() {
+	input := [][]int{{1, 2}, {3}, {1, 2}, {4}}
+
+	result := FromSlice(input).SkipBy([]int{1, 2}, slices.Equal).Slice()
+
+	fmt.Println(result)
+	// Output: [[3] [4]]
+}
@@ -0,0 +1,164 @@
+// Package streamtest provides utilities for testing stream pipelines.
+package streamtest
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nomad-software/stream"
+)
+
+// FakeClock is a stream.Clock that only moves when advanced. It allows stages
+// that depend on time to be tested without sleeping.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	armed   int
+	waiters []*waiter
+}
+
+type waiter struct {
+	clock  *FakeClock
+	c      chan time.Time
+	due    time.Time
+	period time.Duration
+	active bool
+}
+
+// NewFakeClock creates a fake clock set to the passed time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer that fires once the clock has been advanced by d.
+func (c *FakeClock) NewTimer(d time.Duration) stream.Timer {
+	w := &waiter{clock: c, c: make(chan time.Time, 1)}
+	w.arm(d, 0)
+	return timer{w}
+}
+
+// NewTicker creates a ticker that fires every time the clock has been advanced
+// by d.
+func (c *FakeClock) NewTicker(d time.Duration) stream.Ticker {
+	w := &waiter{clock: c, c: make(chan time.Time, 1)}
+	w.arm(d, d)
+	return ticker{w}
+}
+
+// Advance moves the clock forward, firing timers and tickers in order as it
+// passes their due times. Like their counterparts in the time package, a
+// ticker that isn't read in time drops ticks.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	end := c.now.Add(d)
+	for {
+		var next *waiter
+		for _, w := range c.waiters {
+			if w.active && !w.due.After(end) && (next == nil || w.due.Before(next.due)) {
+				next = w
+			}
+		}
+		if next == nil {
+			break
+		}
+		c.now = next.due
+		select {
+		case next.c <- c.now:
+		default:
+		}
+		if next.period > 0 {
+			next.due = next.due.Add(next.period)
+		} else {
+			next.active = false
+		}
+	}
+	c.now = end
+}
+
+// WaitArmed blocks until timers and tickers of the clock have been started or
+// reset n times in total. Stages start and reset timers from their own
+// goroutines, so tests should wait for them before advancing the clock.
+func (c *FakeClock) WaitArmed(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.armed < n {
+		c.cond.Wait()
+	}
+}
+
+func (w *waiter) arm(d, period time.Duration) bool {
+	c := w.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := w.active
+	if !active && w.due.IsZero() {
+		c.waiters = append(c.waiters, w)
+	}
+	w.due = c.now.Add(d)
+	w.period = period
+	w.active = true
+	w.drain()
+	c.armed++
+	c.cond.Broadcast()
+	return active
+}
+
+func (w *waiter) disarm() bool {
+	c := w.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	active := w.active
+	w.active = false
+	w.drain()
+	return active
+}
+
+func (w *waiter) drain() {
+	select {
+	case <-w.c:
+	default:
+	}
+}
+
+type timer struct {
+	*waiter
+}
+
+func (t timer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t timer) Stop() bool {
+	return t.disarm()
+}
+
+func (t timer) Reset(d time.Duration) bool {
+	return t.arm(d, 0)
+}
+
+type ticker struct {
+	*waiter
+}
+
+func (t ticker) C() <-chan time.Time {
+	return t.c
+}
+
+func (t ticker) Stop() {
+	t.disarm()
+}
+
+func (t ticker) Reset(d time.Duration) {
+	t.arm(d, d)
+}
@@ -0,0 +1,55 @@
+package streamtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestFakeClockTimer(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	timer := clock.NewTimer(time.Second)
+
+	clock.Advance(999 * time.Millisecond)
+	assert.Len(t, timer.C(), 0)
+
+	clock.Advance(time.Millisecond)
+	assert.Equal(t, epoch.Add(time.Second), <-timer.C())
+	assert.False(t, timer.Stop())
+
+	assert.False(t, timer.Reset(time.Second))
+	assert.True(t, timer.Stop())
+	clock.Advance(time.Minute)
+	assert.Len(t, timer.C(), 0)
+}
+
+func TestFakeClockTicker(t *testing.T) {
+	clock := NewFakeClock(epoch)
+	ticker := clock.NewTicker(time.Second)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, epoch.Add(time.Second), <-ticker.C())
+
+	clock.Advance(3 * time.Second)
+	assert.Equal(t, epoch.Add(2*time.Second), <-ticker.C())
+	assert.Len(t, ticker.C(), 0)
+
+	ticker.Stop()
+	clock.Advance(time.Minute)
+	assert.Len(t, ticker.C(), 0)
+	assert.Equal(t, epoch.Add(64*time.Second), clock.Now())
+}
+
+func TestFakeClockWaitArmed(t *testing.T) {
+	clock := NewFakeClock(epoch)
+
+	go func() {
+		timer := clock.NewTimer(time.Second)
+		timer.Reset(time.Second)
+	}()
+
+	clock.WaitArmed(2)
+}
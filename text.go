@@ -0,0 +1,72 @@
+package stream
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+)
+
+// Scanning configures how FromLinesWith splits its input.
+type Scanning struct {
+	// Split splits the input into tokens, such as bufio.ScanWords or
+	// bufio.ScanRunes. It defaults to bufio.ScanLines.
+	Split bufio.SplitFunc
+
+	// MaxTokenSize is the size of the longest token that can be read. A
+	// longer token ends the channel with bufio.ErrTooLong. It defaults to
+	// bufio.MaxScanTokenSize.
+	MaxTokenSize int
+}
+
+// FromLines creates a channel that will return the lines read from the
+// io.Reader implementation, without their line endings. The channel will close
+// when the reader is exhausted. Errors reading the lines are available from
+// the channel's Err method once it has closed.
+func FromLines(r io.Reader) Chan[string] {
+	return FromLinesWith(r, Scanning{})
+}
+
+// FromLinesWith creates a channel that will return the tokens read from the
+// io.Reader implementation, using the passed configuration. The channel will
+// close when the reader is exhausted. Errors reading the tokens are available
+// from the channel's Err method once it has closed.
+func FromLinesWith(r io.Reader, config Scanning) Chan[string] {
+	s, output := spawn[Chan[string]]()
+	scanner := bufio.NewScanner(r)
+	if config.Split != nil {
+		scanner.Split(config.Split)
+	}
+	if config.MaxTokenSize > 0 {
+		scanner.Buffer(make([]byte, 0, min(config.MaxTokenSize, 4096)), config.MaxTokenSize)
+	}
+
+	go func() {
+		defer finish(s, output)
+		for scanner.Scan() {
+			if !send(s, output, scanner.Text()) {
+				return
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			s.fail(err)
+		}
+	}()
+
+	return output
+}
+
+// WriteLines writes the string representation of the main channel values to
+// the writer argument, one per line. If the main channel ended because of an
+// error, that error is returned once the channel's values have been written.
+func (c Chan[T]) WriteLines(w io.Writer) error {
+	buffer := bufio.NewWriter(w)
+	for val := range c {
+		if _, err := fmt.Fprintln(buffer, val); err != nil {
+			return err
+		}
+	}
+	if err := buffer.Flush(); err != nil {
+		return err
+	}
+	return c.Err()
+}
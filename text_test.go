@@ -0,0 +1,93 @@
+package stream
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromLines(t *testing.T) {
+	expected := []string{"Lorem ipsum", "dolor sit", "", "amet"}
+	result := FromLines(strings.NewReader("Lorem ipsum\r\ndolor sit\n\namet")).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleFromLines() {
+	r := strings.NewReader("Lorem ipsum\ndolor sit\namet")
+
+	FromLines(r).Map(strings.ToUpper).Print()
+	// Output: [LOREM IPSUM DOLOR SIT AMET]
+}
+
+func TestFromLinesWithSplit(t *testing.T) {
+	expected := []string{"Lorem", "ipsum", "dolor", "sit", "amet"}
+	r := strings.NewReader("Lorem ipsum\ndolor  sit\namet\n")
+	result := FromLinesWith(r, Scanning{Split: bufio.ScanWords}).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestFromLinesWithMaxTokenSize(t *testing.T) {
+	r := strings.NewReader("Lorem\nipsum dolor sit amet\nconsectetur")
+	c := FromLinesWith(r, Scanning{MaxTokenSize: 8})
+
+	assert.Equal(t, []string{"Lorem"}, c.Slice())
+	assert.ErrorIs(t, c.Err(), bufio.ErrTooLong)
+}
+
+func TestFromLinesError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem\nipsum\n"), iotest.ErrReader(failure))
+	c := FromLines(r)
+
+	assert.Equal(t, []string{"Lorem", "ipsum"}, c.Slice())
+	assert.Equal(t, failure, c.Err())
+}
+
+func TestWriteLines(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Iota(1, 4, 1).WriteLines(buf)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "1\n2\n3\n", buf.String())
+}
+
+func TestWriteLinesError(t *testing.T) {
+	failure := errors.New("failure")
+	r := io.MultiReader(strings.NewReader("Lorem\n"), iotest.ErrReader(failure))
+
+	buf := new(bytes.Buffer)
+	err := FromLines(r).WriteLines(buf)
+
+	assert.Equal(t, failure, err)
+	assert.Equal(t, "Lorem\n", buf.String())
+}
+
+func ExampleChan_WriteLines() {
+	r := strings.NewReader("Lorem ipsum\ndolor sit\namet")
+
+	FromLines(r).Filter(func(val string) bool {
+		return strings.Contains(val, " ")
+	}).WriteLines(os.Stdout)
+	// Output:
+	// Lorem ipsum
+	// dolor sit
+}
+
+func TestFromLinesPipeline(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	r := strings.NewReader(strings.Repeat("Lorem ipsum\n", 1000))
+	result := FromLines(r).In(p).Take(2).Slice()
+
+	assert.Equal(t, []string{"Lorem ipsum", "Lorem ipsum"}, result)
+}
@@ -0,0 +1,253 @@
+package stream
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrTimeout is reported by the Err method of a channel returned by Timeout
+// when no value arrived in time.
+var ErrTimeout = errors.New("stream: timed out waiting for a value")
+
+// Tick creates a channel that will return the current time every d. Like
+// time.Tick, ticks are dropped if the channel isn't read in time. This channel
+// will not close by itself and should be limited using other methods.
+func Tick(d time.Duration) Chan[time.Time] {
+	return tick(nil, d)
+}
+
+// Tick creates a channel bound to the pipeline that will return the time of
+// the pipeline's clock every d. Like time.Tick, ticks are dropped if the
+// channel isn't read in time. This channel will not close by itself and should
+// be limited using other methods.
+func (p *Pipeline) Tick(d time.Duration) Chan[time.Time] {
+	return tick(p, d)
+}
+
+// tick creates a ticker channel, bound to the passed pipeline if not nil.
+func tick(p *Pipeline, d time.Duration) Chan[time.Time] {
+	s := newStage()
+	clock := SystemClock
+	capacity := 0
+	if p != nil {
+		clock = p.clock
+		capacity = p.capacity
+	}
+	output := makeOutput[Chan[time.Time]](s, capacity)
+	ticker := clock.NewTicker(d)
+	if p != nil {
+		p.attach(s)
+	}
+
+	go func() {
+		defer finish(s, output)
+		defer ticker.Stop()
+		for {
+			select {
+			case t := <-ticker.C():
+				if !send(s, output, t) {
+					return
+				}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Throttle passes on main channel values no more often than once every d.
+// Values arriving sooner are held back until d has passed, none are dropped.
+func (c Chan[T]) Throttle(d time.Duration) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+	clock := s.clock()
+
+	go func() {
+		defer finish(s, output)
+		var last time.Time
+		first := true
+		for val := range each(s, c) {
+			if !first {
+				wait := last.Add(d).Sub(clock.Now())
+				if wait > 0 && !sleep(s, clock, wait) {
+					return
+				}
+			}
+			first = false
+			last = clock.Now()
+			if !send(s, output, val) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Debounce passes on a main channel value once d has passed without another
+// value arriving, dropping the values in between. The latest value is passed
+// on straight away when the main channel closes.
+func (c Chan[T]) Debounce(d time.Duration) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+	clock := s.clock()
+
+	go func() {
+		defer finish(s, output)
+		var timer Timer
+		var due <-chan time.Time
+		var latest T
+		pending := false
+		for {
+			select {
+			case val, ok := <-c:
+				if !ok {
+					if pending {
+						send(s, output, latest)
+					}
+					return
+				}
+				latest, pending = val, true
+				if timer == nil {
+					timer = clock.NewTimer(d)
+					defer timer.Stop()
+					due = timer.C()
+				} else {
+					timer.Reset(d)
+				}
+			case <-due:
+				if pending && !send(s, output, latest) {
+					return
+				}
+				pending = false
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Sample passes on the latest main channel value every interval. Nothing is
+// passed on if no value arrived since the last interval and a value arriving
+// after the last interval is dropped when the main channel closes.
+func (c Chan[T]) Sample(interval time.Duration) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+	ticker := s.clock().NewTicker(interval)
+
+	go func() {
+		defer finish(s, output)
+		defer ticker.Stop()
+		var latest T
+		pending := false
+		for {
+			select {
+			case val, ok := <-c:
+				if !ok {
+					return
+				}
+				latest, pending = val, true
+			case <-ticker.C():
+				if pending && !send(s, output, latest) {
+					return
+				}
+				pending = false
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// Timeout passes on main channel values until no value arrives within d of
+// the previous one, or of the call for the first value. The returned channel
+// then closes and its Err method reports ErrTimeout. Time spent waiting for
+// values to be read from the returned channel isn't counted.
+func (c Chan[T]) Timeout(d time.Duration) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+	timer := s.clock().NewTimer(d)
+
+	go func() {
+		defer finish(s, output)
+		defer timer.Stop()
+		for {
+			select {
+			case val, ok := <-c:
+				if !ok {
+					return
+				}
+				timer.Stop()
+				if !send(s, output, val) {
+					return
+				}
+				timer.Reset(d)
+			case <-timer.C():
+				s.fail(ErrTimeout)
+				return
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// delayed holds a value along with the time it is due to be passed on.
+type delayed[T any] struct {
+	due time.Time
+	val T
+}
+
+// Delay passes on each main channel value d after it arrived. Values arriving
+// while others are being delayed are queued, so a source that is faster than
+// the stages reading from it makes the queue grow. Throttle can be used to
+// limit such sources.
+func (c Chan[T]) Delay(d time.Duration) Chan[T] {
+	s, output := spawn[Chan[T]](c)
+	clock := s.clock()
+
+	go func() {
+		defer finish(s, output)
+		var timer Timer
+		var queue []delayed[T]
+		input := c
+		for input != nil || len(queue) > 0 {
+			var ready chan<- T
+			var due <-chan time.Time
+			var head T
+			if len(queue) > 0 {
+				head = queue[0].val
+				wait := queue[0].due.Sub(clock.Now())
+				if wait <= 0 {
+					ready = output
+				} else if timer == nil {
+					timer = clock.NewTimer(wait)
+					defer timer.Stop()
+					due = timer.C()
+				} else {
+					timer.Reset(wait)
+					due = timer.C()
+				}
+			}
+			select {
+			case val, ok := <-input:
+				if !ok {
+					input = nil
+					continue
+				}
+				queue = append(queue, delayed[T]{due: clock.Now().Add(d), val: val})
+			case <-due:
+			case ready <- head:
+				queue = queue[1:]
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
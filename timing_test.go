@@ -0,0 +1,222 @@
+package stream_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nomad-software/stream"
+	"github.com/nomad-software/stream/streamtest"
+	"github.com/stretchr/testify/assert"
+)
+
+var epoch = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestTick(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	c := p.Tick(time.Second)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, epoch.Add(time.Second), <-c)
+	clock.Advance(500 * time.Millisecond)
+	assertPending(t, c)
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, epoch.Add(2*time.Second), <-c)
+}
+
+func TestTickThrottle(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	c := stream.MapTo(p.Tick(time.Second), func(t time.Time) time.Duration {
+		return t.Sub(epoch)
+	}).Throttle(2 * time.Second)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, time.Second, <-c)
+	clock.Advance(time.Second)
+	clock.WaitArmed(2)
+	clock.Advance(time.Second)
+	assert.Equal(t, 2*time.Second, <-c)
+}
+
+func ExampleTick() {
+	result := stream.Tick(time.Millisecond).Take(3).Slice()
+
+	fmt.Println(len(result))
+	// Output: 3
+}
+
+func TestThrottle(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	start := clock.Now()
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	c := stream.FromSlice([]int{1, 2, 3}).In(p).Throttle(time.Second)
+
+	assert.Equal(t, 1, <-c)
+	assert.Equal(t, start, clock.Now())
+
+	clock.WaitArmed(1)
+	clock.Advance(time.Second)
+	assert.Equal(t, 2, <-c)
+
+	clock.WaitArmed(2)
+	clock.Advance(500 * time.Millisecond)
+	assertPending(t, c)
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, 3, <-c)
+	assert.Equal(t, start.Add(2*time.Second), clock.Now())
+
+	assert.Equal(t, []int{}, c.Slice())
+}
+
+func ExampleChan_Throttle() {
+	result := stream.Iota(1, 4, 1).Throttle(time.Millisecond).Slice()
+
+	fmt.Println(result)
+	// Output: [1 2 3]
+}
+
+func TestDebounce(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.Chan[int](input).In(p).Debounce(time.Second)
+
+	input <- 1
+	clock.WaitArmed(1)
+	clock.Advance(500 * time.Millisecond)
+	input <- 2
+	clock.WaitArmed(2)
+	clock.Advance(500 * time.Millisecond)
+	input <- 3
+	clock.WaitArmed(3)
+	clock.Advance(500 * time.Millisecond)
+	assertPending(t, c)
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, 3, <-c)
+
+	input <- 4
+	close(input)
+	assert.Equal(t, []int{4}, c.Slice())
+}
+
+func ExampleChan_Debounce() {
+	result := stream.FromString("Lorem ipsum dolor", " ").Debounce(time.Second).Slice()
+
+	fmt.Println(result)
+	// Output: [dolor]
+}
+
+func TestSample(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.Chan[int](input).In(p).Sample(time.Second)
+
+	input <- 1
+	input <- 2
+	clock.Advance(time.Second)
+	assert.Equal(t, 2, <-c)
+
+	input <- 3
+	clock.Advance(time.Second)
+	assert.Equal(t, 3, <-c)
+
+	clock.Advance(time.Second)
+	assertPending(t, c)
+
+	input <- 4
+	close(input)
+	assert.Equal(t, []int{}, c.Slice())
+}
+
+func TestTimeout(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.Chan[int](input).In(p).Timeout(time.Second)
+
+	input <- 1
+	assert.Equal(t, 1, <-c)
+	clock.WaitArmed(2)
+	clock.Advance(900 * time.Millisecond)
+
+	input <- 2
+	assert.Equal(t, 2, <-c)
+	clock.WaitArmed(3)
+	clock.Advance(time.Second)
+
+	assert.Equal(t, []int{}, c.Slice())
+	assert.ErrorIs(t, c.Err(), stream.ErrTimeout)
+}
+
+func TestTimeoutClosed(t *testing.T) {
+	c := stream.Iota(1, 4, 1).Timeout(time.Minute)
+
+	assert.Equal(t, []int{1, 2, 3}, c.Slice())
+	assert.NoError(t, c.Err())
+}
+
+func ExampleChan_Timeout() {
+	c := stream.FromChannel(make(chan int)).Timeout(time.Millisecond)
+
+	fmt.Println(c.Slice(), c.Err())
+	// Output: [] stream: timed out waiting for a value
+}
+
+func TestDelay(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	start := clock.Now()
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.Chan[int](input).In(p).Delay(time.Second)
+
+	input <- 1
+	clock.WaitArmed(1)
+	clock.Advance(500 * time.Millisecond)
+	input <- 2
+	clock.WaitArmed(2)
+	clock.Advance(500 * time.Millisecond)
+
+	assert.Equal(t, 1, <-c)
+	assert.Equal(t, start.Add(time.Second), clock.Now())
+
+	clock.WaitArmed(3)
+	clock.Advance(500 * time.Millisecond)
+	assert.Equal(t, 2, <-c)
+
+	close(input)
+	assert.Equal(t, []int{}, c.Slice())
+}
+
+func ExampleChan_Delay() {
+	result := stream.Iota(1, 4, 1).Delay(time.Millisecond).Slice()
+
+	fmt.Println(result)
+	// Output: [1 2 3]
+}
+
+// assertPending asserts that no value is ready to be read from the channel.
+func assertPending[T any](t *testing.T, c stream.Chan[T]) {
+	t.Helper()
+	select {
+	case val, ok := <-c:
+		t.Errorf("unexpected value %v (open: %v)", val, ok)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
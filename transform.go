@@ -0,0 +1,131 @@
+package stream
+
+// MapTo converts the passed channel's values to another type based on the
+// passed function. The passed function is called once for each value.
+func MapTo[T, U any](c Chan[T], f func(val T) U) Chan[U] {
+	s, output := spawn[Chan[U]](c)
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if !send(s, output, f(val)) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// FlatMap converts each of the passed channel's values to a channel based on
+// the passed function and returns the values of those channels, in order. A
+// channel that ends because of an error ends the returned channel with that
+// error. The passed function is called once for each value.
+func FlatMap[T, U any](c Chan[T], f func(val T) Chan[U]) Chan[U] {
+	s, output := spawn[Chan[U]](c)
+
+	go func() {
+		defer finish(s, output)
+		for val := range each(s, c) {
+			if !flatten(s, output, f(val)) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// flatten passes on the values of a channel returned by the function of the
+// passed FlatMap stage. The stage feeding the channel is read like an input,
+// joining the FlatMap stage's pipeline, and is unwound once it has been read
+// or the FlatMap stage stops reading it. It returns false if the FlatMap stage
+// should exit.
+func flatten[U any](s *stage, output chan U, inner Chan[U]) bool {
+	u := lookup(inner)
+	if u != nil {
+		u.retain()
+		defer u.release(true)
+		if p := s.bound(); p != nil {
+			p.attach(u)
+		}
+	}
+	for val := range each(s, inner) {
+		if !send(s, output, val) {
+			return false
+		}
+	}
+	if err := inner.Err(); err != nil {
+		s.fail(err)
+		return false
+	}
+	return true
+}
+
+// ReduceTo reduces the passed channel's values to one value of another type
+// based on the passed function, starting with the passed initial value. Nothing
+// is returned if the passed channel ended because of an error or the stage was
+// stopped. The passed function is called once for each value.
+func ReduceTo[T, A any](c Chan[T], init A, f func(acc A, val T) A) Chan[A] {
+	s, output := spawn[Chan[A]](c)
+
+	go func() {
+		defer finish(s, output)
+		acc := init
+		for val := range each(s, c) {
+			acc = f(acc, val)
+		}
+		if s.interrupted() {
+			return
+		}
+		send(s, output, acc)
+	}()
+
+	return output
+}
+
+// ScanTo passes on the running result of reducing the passed channel's values
+// to a value of another type based on the passed function, starting with the
+// passed initial value. The passed function is called once for each value.
+func ScanTo[T, A any](c Chan[T], init A, f func(acc A, val T) A) Chan[A] {
+	s, output := spawn[Chan[A]](c)
+
+	go func() {
+		defer finish(s, output)
+		acc := init
+		for val := range each(s, c) {
+			acc = f(acc, val)
+			if !send(s, output, acc) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// ZipWith combines the next values of the passed channels based on the passed
+// function. The returned channel closes when either of the passed channels
+// closes. The passed function is called once for each pair of values.
+func ZipWith[A, B, C any](a Chan[A], b Chan[B], f func(a A, b B) C) Chan[C] {
+	s, output := spawn[Chan[C]](a, b)
+
+	go func() {
+		defer finish(s, output)
+		for {
+			x, ok := recv(s, a)
+			if !ok {
+				return
+			}
+			y, ok := recv(s, b)
+			if !ok {
+				return
+			}
+			if !send(s, output, f(x, y)) {
+				return
+			}
+		}
+	}()
+
+	return output
+}
@@ -0,0 +1,150 @@
+package stream
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMapTo(t *testing.T) {
+	expected := []int{5, 5, 5, 3, 4}
+	result := MapTo(FromString("Lorem ipsum dolor sit amet", " "), func(val string) int {
+		return len(val)
+	}).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleMapTo() {
+	result := MapTo(Iota(1, 6, 1), strconv.Itoa).Slice()
+
+	fmt.Printf("%q\n", result)
+	// Output: ["1" "2" "3" "4" "5"]
+}
+
+func TestFlatMap(t *testing.T) {
+	expected := []rune("Loremipsum")
+	result := FlatMap(FromString("Lorem ipsum", " "), FromRunes).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func TestFlatMapPipeline(t *testing.T) {
+	p := NewPipeline()
+	c := FlatMap(Iota(1, 4, 1).In(p), Repeat[int]).Take(3)
+
+	assert.Equal(t, []int{1, 1, 1}, c.Slice())
+	p.Stop()
+}
+
+func TestFlatMapUnwinds(t *testing.T) {
+	p := NewPipeline()
+	defer p.Stop()
+
+	c := FlatMap(Repeat(1).In(p), func(val int) Chan[int] { return Repeat(2) }).Take(3)
+
+	assert.Equal(t, []int{2, 2, 2}, c.Slice())
+	p.Wait()
+	assert.NoError(t, p.Err())
+}
+
+func TestFlatMapUnbound(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	c := FlatMap(Iota(1, 3, 1), func(val int) Chan[int] {
+		return Repeat(val).Map(func(val int) int { return val * 2 })
+	})
+
+	assert.Equal(t, 2, <-c)
+	abandon(c)
+	c.Drain()
+	assertGoroutines(t, before)
+}
+
+func TestFlatMapError(t *testing.T) {
+	failure := errors.New("failure")
+	c := FlatMap(Iota(1, 3, 1), func(val int) Chan[byte] {
+		return FromReader(io.MultiReader(strings.NewReader("a"), iotest.ErrReader(failure)))
+	})
+
+	assert.Equal(t, []byte("a"), c.Slice())
+	assert.ErrorIs(t, c.Err(), failure)
+}
+
+func ExampleFlatMap() {
+	result := FlatMap(Iota(1, 4, 1), func(val int) Chan[int] {
+		return Repeat(val).Take(val)
+	}).Slice()
+
+	fmt.Println(result)
+	// Output: [1 2 2 3 3 3]
+}
+
+func TestReduceTo(t *testing.T) {
+	expected := 27
+	result := ReduceTo(FromString("Lorem ipsum dolor sit amet", " "), 0, func(acc int, val string) int {
+		return acc + len(val) + 1
+	}).Pop()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleReduceTo() {
+	result := ReduceTo(Iota(1, 6, 1), "", func(acc string, val int) string {
+		return acc + strconv.Itoa(val)
+	}).Pop()
+
+	fmt.Println(result)
+	// Output: 12345
+}
+
+func TestScanTo(t *testing.T) {
+	expected := []int{6, 12, 18}
+	result := ScanTo(FromString("Lorem ipsum dolor", " "), 0, func(acc int, val string) int {
+		return acc + len(val) + 1
+	}).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleScanTo() {
+	result := ScanTo(Iota(1, 4, 1), "", func(acc string, val int) string {
+		return acc + strconv.Itoa(val)
+	}).Slice()
+
+	fmt.Println(result)
+	// Output: [1 12 123]
+}
+
+func TestZipWith(t *testing.T) {
+	expected := []string{"LOREM", "ipsum", "DOLOR"}
+	words := FromString("Lorem ipsum dolor sit amet", " ")
+	upper := Cycle([]bool{true, false}).Take(3)
+
+	result := ZipWith(words, upper, func(word string, upper bool) string {
+		if upper {
+			return strings.ToUpper(word)
+		}
+		return word
+	}).Slice()
+
+	assert.Equal(t, expected, result)
+}
+
+func ExampleZipWith() {
+	names := FromSlice([]string{"a", "b", "c"})
+
+	result := ZipWith(Iota(1, 10, 1), names, func(i int, name string) string {
+		return name + strconv.Itoa(i)
+	}).Slice()
+
+	fmt.Println(result)
+	// Output: [a1 b2 c3]
+}
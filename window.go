@@ -0,0 +1,180 @@
+package stream
+
+import (
+	"fmt"
+	"time"
+)
+
+// Window channel types. They are separate from Chan so that its methods can
+// return them, and can be converted to a Chan to chain further operations.
+type ChanSlice[T any] chan []T
+type ChanWindow[T any] chan Window[T]
+
+// Window holds the values of the main channel that arrived between its start
+// and end time.
+type Window[T any] struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Vals  []T       `json:"vals"`
+}
+
+// Window groups the main channel values into slices of size values. A new
+// slice is started every step values, so a step equal to size gives tumbling
+// windows, a smaller step gives overlapping sliding windows and a larger step
+// skips the values in between. When the main channel closes, values not yet
+// passed on in a slice are passed on in a final, shorter slice. It panics if
+// size or step is less than 1.
+func (c Chan[T]) Window(size, step int) ChanSlice[T] {
+	if size < 1 || step < 1 {
+		panic(fmt.Sprintf("stream: window size %d and step %d must be positive", size, step))
+	}
+	s, output := spawn[ChanSlice[T]](c)
+
+	go func() {
+		defer finish(s, output)
+		window := make([]T, 0, size)
+		skip, fresh := 0, 0
+		for val := range each(s, c) {
+			if skip > 0 {
+				skip--
+				continue
+			}
+			window = append(window, val)
+			fresh++
+			if len(window) < size {
+				continue
+			}
+			if !send(s, output, window) {
+				return
+			}
+			next := make([]T, 0, size)
+			if step < size {
+				next = append(next, window[step:]...)
+			} else {
+				skip = step - size
+			}
+			window, fresh = next, 0
+		}
+		if fresh > 0 {
+			send(s, output, window)
+		}
+	}()
+
+	return output
+}
+
+// WindowTime groups the main channel values by the time they arrived into
+// windows lasting size. A new window is started every step, beginning when
+// WindowTime is called, so a step equal to size gives tumbling windows and a
+// smaller step gives overlapping sliding windows. Every window is passed on
+// once it ends, even if empty. When the main channel closes, windows holding
+// values are passed on straight away, ending at the time it closed. It panics
+// if size or step is not positive.
+func (c Chan[T]) WindowTime(size, step time.Duration) ChanWindow[T] {
+	if size <= 0 || step <= 0 {
+		panic(fmt.Sprintf("stream: window size %v and step %v must be positive", size, step))
+	}
+	s, output := spawn[ChanWindow[T]](c)
+	clock := s.clock()
+	start := clock.Now()
+	timer := clock.NewTimer(min(size, step))
+
+	go func() {
+		defer finish(s, output)
+		defer timer.Stop()
+		open := []Window[T]{{Start: start, End: start.Add(size)}}
+		next := start.Add(step)
+		for {
+			select {
+			case val, ok := <-c:
+				if !ok {
+					now := clock.Now()
+					for _, w := range open {
+						if len(w.Vals) > 0 {
+							w.End = now
+							if !send(s, output, w) {
+								return
+							}
+						}
+					}
+					return
+				}
+				for i := range open {
+					open[i].Vals = append(open[i].Vals, val)
+				}
+			case <-timer.C():
+				now := clock.Now()
+				for !next.After(now) {
+					open = append(open, Window[T]{Start: next, End: next.Add(size)})
+					next = next.Add(step)
+				}
+				for len(open) > 0 && !open[0].End.After(now) {
+					if !send(s, output, open[0]) {
+						return
+					}
+					open = open[1:]
+				}
+				due := next
+				if len(open) > 0 && open[0].End.Before(due) {
+					due = open[0].End
+				}
+				timer.Reset(due.Sub(now))
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
+
+// WindowSession groups the main channel values into sessions. A session starts
+// with the first value to arrive and is passed on once gap has passed without
+// another value arriving, ending gap after its last value. When the main
+// channel closes, an open session is passed on straight away, ending at the
+// time it closed.
+func (c Chan[T]) WindowSession(gap time.Duration) ChanWindow[T] {
+	s, output := spawn[ChanWindow[T]](c)
+	clock := s.clock()
+
+	go func() {
+		defer finish(s, output)
+		var timer Timer
+		var due <-chan time.Time
+		var session Window[T]
+		for {
+			select {
+			case val, ok := <-c:
+				if !ok {
+					if len(session.Vals) > 0 {
+						session.End = clock.Now()
+						send(s, output, session)
+					}
+					return
+				}
+				now := clock.Now()
+				if len(session.Vals) == 0 {
+					session.Start = now
+				}
+				session.End = now.Add(gap)
+				session.Vals = append(session.Vals, val)
+				if timer == nil {
+					timer = clock.NewTimer(gap)
+					defer timer.Stop()
+					due = timer.C()
+				} else {
+					timer.Reset(gap)
+				}
+			case <-due:
+				if len(session.Vals) > 0 && !send(s, output, session) {
+					return
+				}
+				session = Window[T]{}
+			case <-s.stop:
+				return
+			}
+		}
+	}()
+
+	return output
+}
@@ -0,0 +1,141 @@
+package stream_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nomad-software/stream"
+	"github.com/nomad-software/stream/streamtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWindowTumbling(t *testing.T) {
+	expected := [][]int{{1, 2, 3}, {4, 5, 6}, {7}}
+	c := stream.Iota(1, 8, 1).Window(3, 3)
+
+	assert.Equal(t, expected, stream.Chan[[]int](c).Slice())
+}
+
+func TestWindowSliding(t *testing.T) {
+	expected := [][]int{{1, 2, 3}, {2, 3, 4}, {3, 4, 5}}
+	c := stream.Iota(1, 6, 1).Window(3, 1)
+
+	assert.Equal(t, expected, stream.Chan[[]int](c).Slice())
+}
+
+func TestWindowHopping(t *testing.T) {
+	expected := [][]int{{1, 2}, {4, 5}, {7}}
+	c := stream.Iota(1, 8, 1).Window(2, 3)
+
+	assert.Equal(t, expected, stream.Chan[[]int](c).Slice())
+}
+
+func ExampleChan_Window() {
+	for window := range stream.Iota(1, 6, 1).Window(3, 2) {
+		fmt.Println(window)
+	}
+	// Output:
+	// [1 2 3]
+	// [3 4 5]
+}
+
+func TestWindowInvalid(t *testing.T) {
+	c := make(stream.Chan[int])
+
+	assert.Panics(t, func() { c.Window(0, 1) })
+	assert.Panics(t, func() { c.Window(3, 0) })
+	assert.Panics(t, func() { c.Window(3, -1) })
+	assert.Panics(t, func() { c.WindowTime(0, time.Second) })
+	assert.Panics(t, func() { c.WindowTime(time.Second, 0) })
+}
+
+func TestWindowTime(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.Chan[int](input).In(p).WindowTime(2*time.Second, time.Second)
+
+	input <- 1
+	clock.Advance(time.Second)
+	clock.WaitArmed(2)
+	input <- 2
+	clock.Advance(time.Second)
+	assert.Equal(t, stream.Window[int]{
+		Start: epoch,
+		End:   epoch.Add(2 * time.Second),
+		Vals:  []int{1, 2},
+	}, <-c)
+
+	clock.WaitArmed(3)
+	clock.Advance(time.Second)
+	assert.Equal(t, stream.Window[int]{
+		Start: epoch.Add(time.Second),
+		End:   epoch.Add(3 * time.Second),
+		Vals:  []int{2},
+	}, <-c)
+
+	clock.WaitArmed(4)
+	input <- 3
+	close(input)
+	assert.Equal(t, []stream.Window[int]{
+		{Start: epoch.Add(2 * time.Second), End: epoch.Add(3 * time.Second), Vals: []int{3}},
+		{Start: epoch.Add(3 * time.Second), End: epoch.Add(3 * time.Second), Vals: []int{3}},
+	}, stream.Chan[stream.Window[int]](c).Slice())
+}
+
+func TestWindowTimeEmpty(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	c := stream.Chan[int](make(chan int)).In(p).WindowTime(time.Second, time.Second)
+
+	clock.Advance(time.Second)
+	assert.Equal(t, stream.Window[int]{
+		Start: epoch,
+		End:   epoch.Add(time.Second),
+	}, <-c)
+}
+
+func ExampleChan_WindowTime() {
+	for window := range stream.Iota(1, 4, 1).WindowTime(time.Hour, time.Hour) {
+		fmt.Println(window.Vals)
+	}
+	// Output: [1 2 3]
+}
+
+func TestWindowSession(t *testing.T) {
+	clock := streamtest.NewFakeClock(epoch)
+	p := stream.NewPipeline(stream.WithClock(clock))
+	defer p.Stop()
+
+	input := make(chan int)
+	c := stream.Chan[int](input).In(p).WindowSession(time.Second)
+
+	input <- 1
+	clock.WaitArmed(1)
+	clock.Advance(500 * time.Millisecond)
+	input <- 2
+	clock.WaitArmed(2)
+	clock.Advance(time.Second)
+	assert.Equal(t, stream.Window[int]{
+		Start: epoch,
+		End:   epoch.Add(1500 * time.Millisecond),
+		Vals:  []int{1, 2},
+	}, <-c)
+
+	clock.Advance(time.Minute)
+	assertPending(t, stream.Chan[stream.Window[int]](c))
+
+	input <- 3
+	clock.WaitArmed(3)
+	close(input)
+	assert.Equal(t, []stream.Window[int]{{
+		Start: epoch.Add(time.Minute + 1500*time.Millisecond),
+		End:   epoch.Add(time.Minute + 1500*time.Millisecond),
+		Vals:  []int{3},
+	}}, stream.Chan[stream.Window[int]](c).Slice())
+}